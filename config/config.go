@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -10,16 +13,235 @@ import (
 type Config struct {
 	TelegramToken string
 	GPTToken      string
+
+	// LLMProvider — "openai" (по умолчанию, любой OpenAI-совместимый API) или "fake"
+	LLMProvider string
+	GPTBaseURL  string
+	GPTModel    string
+	// GPTTimeout — предел одной попытки запроса, GPTMaxRetries — число повторов
+	GPTTimeout    time.Duration
+	GPTMaxRetries int
+	// LLMFakeScript — JSON сценарий ответов для провайдера "fake" (пусто — всегда "{}")
+	LLMFakeScript string
+
+	// PromptsDir — каталог с дополнительными версиями промптов (<имя>/<версия>.tmpl)
+	PromptsDir string
+	// PromptExperiments — A/B эксперименты: "profile:v3:20,task:v3:50"
+	PromptExperiments string
+
+	// GPTPriceInput и GPTPriceOutput — цена в долларах за миллион токенов
+	// промпта и ответа для оценки стоимости
+	GPTPriceInput  float64
+	GPTPriceOutput float64
+	// UserDailyTokens — дневной лимит токенов на пользователя (0 — без лимита)
+	UserDailyTokens int
+
+	// LLMCacheTTL — сколько хранить ответы модели на повторные запросы (0 — не кэшировать),
+	// LLMCacheDir — каталог кэша на диске (пусто — только в памяти)
+	LLMCacheTTL        time.Duration
+	LLMCacheMaxEntries int
+	LLMCacheMaxBytes   int
+	LLMCacheDir        string
+
+	// AdminIDs — Telegram ID администраторов (/usage, /usage_export)
+	AdminIDs []int64
+
+	// InterviewMode — "form" (анкета, по умолчанию) или "chat": интервью профиля
+	// ведет модель в форме беседы не больше ChatMaxTurns вопросов и ChatMaxTokens токенов
+	InterviewMode string
+	ChatMaxTurns  int
+	ChatMaxTokens int
+
+	// MatchWeights — веса факторов подбора поверх значений по умолчанию:
+	// "skills=0.6,interests=0.2,availability=0.2"; MatchThreshold — минимальная оценка
+	MatchWeights      string
+	MatchThreshold    float64
+	MatchDeadlineDays int
+	// MatchInterestSimilarity — с какой близости векторов интерес совпадает с задачей
+	MatchInterestSimilarity float64
+
+	// EmbeddingProvider — "local" (по умолчанию, без обращения к API) или "openai":
+	// векторы интересов и задач считает EmbeddingModel по адресу GPTBaseURL
+	EmbeddingProvider string
+	EmbeddingModel    string
+	// EmbeddingPrice — цена в долларах за миллион токенов EmbeddingModel
+	EmbeddingPrice float64
+	// EmbeddingRefreshInterval — как часто пересчитывать устаревшие векторы
+	// (сохраненные до их появления или другой моделью); 0 — не пересчитывать
+	EmbeddingRefreshInterval time.Duration
+
+	// BotWorkers — сколько обновлений обрабатывается параллельно
+	BotWorkers int
+
+	// StorageDriver — "memory" (по умолчанию) или "sqlite"
+	StorageDriver string
+	SQLitePath    string
+	// SeedDemoTasks — добавить в хранилище демонстрационные задачи при старте
+	// (по умолчанию только в памяти, где без них подбирать не из чего)
+	SeedDemoTasks bool
+
+	// SessionTTL — через сколько неактивности интервью отменяется (0 — никогда)
+	SessionTTL time.Duration
+	// SessionRemindAfter — когда напомнить о брошенном интервью (0 — не напоминать)
+	SessionRemindAfter   time.Duration
+	SessionSweepInterval time.Duration
+
+	// QuestionBankPath — YAML/JSON файл с вопросами интервью (пусто — встроенный банк)
+	QuestionBankPath   string
+	QuestionBankReload time.Duration
 }
 
 func LoadConfig() *Config {
 	_ = godotenv.Load()
 	cfg := &Config{
-		TelegramToken: os.Getenv("TELEGRAM_TOKEN"),
-		GPTToken:      os.Getenv("GPT_TOKEN"),
+		TelegramToken:     os.Getenv("TELEGRAM_TOKEN"),
+		GPTToken:          os.Getenv("GPT_TOKEN"),
+		LLMProvider:       getEnv("LLM_PROVIDER", "openai"),
+		GPTBaseURL:        getEnv("GPT_BASE_URL", "https://api.openai.com/v1"),
+		GPTModel:          getEnv("GPT_MODEL", "gpt-4-1106-preview"),
+		GPTTimeout:        getDuration("GPT_TIMEOUT", 60*time.Second),
+		GPTMaxRetries:     getInt("GPT_MAX_RETRIES", 3),
+		LLMFakeScript:     os.Getenv("LLM_FAKE_SCRIPT"),
+		PromptsDir:        os.Getenv("PROMPTS_DIR"),
+		PromptExperiments: os.Getenv("PROMPT_EXPERIMENTS"),
+
+		GPTPriceInput:   getFloat("GPT_PRICE_INPUT", 10),
+		GPTPriceOutput:  getFloat("GPT_PRICE_OUTPUT", 30),
+		UserDailyTokens: getInt("USER_DAILY_TOKENS", 200000),
+		AdminIDs:        getIDs("ADMIN_IDS"),
+
+		LLMCacheTTL:        getDuration("LLM_CACHE_TTL", 24*time.Hour),
+		LLMCacheMaxEntries: getInt("LLM_CACHE_MAX_ENTRIES", 1000),
+		LLMCacheMaxBytes:   getInt("LLM_CACHE_MAX_BYTES", 8<<20),
+		LLMCacheDir:        os.Getenv("LLM_CACHE_DIR"),
+
+		InterviewMode: getEnv("INTERVIEW_MODE", "form"),
+		ChatMaxTurns:  getInt("CHAT_MAX_TURNS", 12),
+		ChatMaxTokens: getInt("CHAT_MAX_TOKENS", 30000),
+
+		MatchWeights:      os.Getenv("MATCH_WEIGHTS"),
+		MatchThreshold:    getFloat("MATCH_THRESHOLD", 0.3),
+		MatchDeadlineDays: getInt("MATCH_DEADLINE_DAYS", 7),
+
+		MatchInterestSimilarity: getFloat("MATCH_INTEREST_SIMILARITY", 0.25),
+
+		EmbeddingProvider: getEnv("EMBEDDING_PROVIDER", "local"),
+		EmbeddingModel:    getEnv("EMBEDDING_MODEL", "text-embedding-3-small"),
+		EmbeddingPrice:    getFloat("EMBEDDING_PRICE", 0.02),
+
+		EmbeddingRefreshInterval: getDuration("EMBEDDING_REFRESH_INTERVAL", 10*time.Minute),
+
+		BotWorkers:    getInt("BOT_WORKERS", 8),
+		StorageDriver: getEnv("STORAGE_DRIVER", "memory"),
+		SQLitePath:    getEnv("SQLITE_PATH", "viget.db"),
+
+		SessionTTL:           getDuration("SESSION_TTL", 72*time.Hour),
+		SessionRemindAfter:   getDuration("SESSION_REMIND_AFTER", 24*time.Hour),
+		SessionSweepInterval: getDuration("SESSION_SWEEP_INTERVAL", 10*time.Minute),
+
+		QuestionBankPath:   os.Getenv("QUESTION_BANK_PATH"),
+		QuestionBankReload: getDuration("QUESTION_BANK_RELOAD", 30*time.Second),
 	}
-	if cfg.TelegramToken == "" || cfg.GPTToken == "" {
+	if cfg.TelegramToken == "" {
 		log.Fatal("Missing required environment variables")
 	}
+	switch cfg.LLMProvider {
+	case "openai":
+		// Собственной модели по другому адресу токен может быть не нужен
+		if cfg.GPTToken == "" && cfg.GPTBaseURL == "https://api.openai.com/v1" {
+			log.Fatal("Missing required environment variables")
+		}
+	case "fake":
+	default:
+		log.Fatalf("Unknown LLM_PROVIDER: %s", cfg.LLMProvider)
+	}
+	switch cfg.EmbeddingProvider {
+	case "openai":
+		if cfg.GPTToken == "" && cfg.GPTBaseURL == "https://api.openai.com/v1" {
+			log.Fatal("Missing required environment variables")
+		}
+	case "local":
+	default:
+		log.Fatalf("Unknown EMBEDDING_PROVIDER: %s", cfg.EmbeddingProvider)
+	}
+	if cfg.InterviewMode != "form" && cfg.InterviewMode != "chat" {
+		log.Fatalf("Unknown INTERVIEW_MODE: %s", cfg.InterviewMode)
+	}
+	if cfg.StorageDriver != "memory" && cfg.StorageDriver != "sqlite" {
+		log.Fatalf("Unknown STORAGE_DRIVER: %s", cfg.StorageDriver)
+	}
+	cfg.SeedDemoTasks = getBool("SEED_DEMO_TASKS", cfg.StorageDriver == "memory")
 	return cfg
 }
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return d
+}
+
+func getBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %q", key, value)
+	}
+	return b
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid %s: %q", key, value)
+	}
+	return n
+}
+
+func getFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		log.Fatalf("Invalid %s: %q", key, value)
+	}
+	return f
+}
+
+// getIDs читает список Telegram ID через запятую
+func getIDs(key string) []int64 {
+	var ids []int64
+	for _, field := range strings.Split(os.Getenv(key), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid %s: %q", key, field)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
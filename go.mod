@@ -5,4 +5,6 @@ go 1.24.4
 require (
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/joho/godotenv v1.5.1
+	github.com/mattn/go-sqlite3 v1.14.33
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,188 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"viget-mvp/internal/extractor"
+	"viget-mvp/internal/semantic"
+	"viget-mvp/internal/usage"
+	"viget-mvp/pkg/gpt"
+)
+
+// topUsers — сколько пользователей показывать в отчете о расходе
+const topUsers = 10
+
+var featureNames = map[string]string{
+	extractor.FeatureAnswerAnalysis:    "анализ ответов",
+	extractor.FeatureProfileExtraction: "извлечение профиля",
+	extractor.FeatureTaskExtraction:    "извлечение задачи",
+	extractor.FeatureChatInterview:     "интервью-беседа",
+	semantic.Feature:                   "векторы интересов",
+}
+
+// SetAdmins задает Telegram ID администраторов, которым доступны служебные команды
+func (h *Handler) SetAdmins(ids []int64) {
+	h.admins = make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		h.admins[id] = true
+	}
+}
+
+// SetUsageTracker включает команды /usage и /usage_export
+func (h *Handler) SetUsageTracker(tracker *usage.Tracker) {
+	h.usage = tracker
+}
+
+// SetLLMCache добавляет метрики кэша ответов модели в /usage
+func (h *Handler) SetLLMCache(cache *gpt.Cache) {
+	h.llmCache = cache
+}
+
+func (h *Handler) isAdmin(userID int64) bool {
+	return h.admins[userID]
+}
+
+// usageDay разбирает необязательный аргумент команды: день в формате 2006-01-02
+func (h *Handler) usageDay(text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return h.usage.Today(), true
+	}
+	if fields[1] == "all" {
+		return "", true
+	}
+	if _, err := time.Parse(usage.DayFormat, fields[1]); err != nil {
+		return "", false
+	}
+	return fields[1], true
+}
+
+// handleUsage показывает расход токенов за день по функциям и пользователям
+func (h *Handler) handleUsage(userID int64, text string) {
+	if h.usage == nil {
+		h.sendMessage(userID, "📊 Учет расхода не включен.")
+		return
+	}
+	day, ok := h.usageDay(text)
+	if !ok || day == "" {
+		h.sendMessage(userID, "Использование: /usage [ГГГГ-ММ-ДД]")
+		return
+	}
+
+	records := h.usage.Records(day)
+	if len(records) == 0 {
+		h.sendMessage(userID, fmt.Sprintf("📊 За %s обращений к модели не было.", day))
+		return
+	}
+
+	var total usage.Totals
+	byFeature := make(map[string]*usage.Totals)
+	byUser := make(map[int64]*usage.Totals)
+	for _, r := range records {
+		total.Add(r.Totals)
+		if byFeature[r.Feature] == nil {
+			byFeature[r.Feature] = &usage.Totals{}
+		}
+		byFeature[r.Feature].Add(r.Totals)
+		if byUser[r.UserID] == nil {
+			byUser[r.UserID] = &usage.Totals{}
+		}
+		byUser[r.UserID].Add(r.Totals)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📊 *Расход модели за %s*\n\n", day)
+	fmt.Fprintf(&b, "Всего: %s\n\n", formatTotals(total))
+
+	b.WriteString("*По функциям:*\n")
+	features := make([]string, 0, len(byFeature))
+	for feature := range byFeature {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		fmt.Fprintf(&b, "• %s: %s\n", featureName(feature), formatTotals(*byFeature[feature]))
+	}
+
+	b.WriteString("\n*Пользователи:*\n")
+	users := make([]int64, 0, len(byUser))
+	for id := range byUser {
+		users = append(users, id)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		a, b := byUser[users[i]], byUser[users[j]]
+		if a.Tokens() != b.Tokens() {
+			return a.Tokens() > b.Tokens()
+		}
+		return users[i] < users[j]
+	})
+	if len(users) > topUsers {
+		users = users[:topUsers]
+	}
+	for _, id := range users {
+		name := fmt.Sprintf("%d", id)
+		if id == 0 {
+			name = "без пользователя"
+		}
+		fmt.Fprintf(&b, "• %s: %s\n", name, formatTotals(*byUser[id]))
+	}
+
+	if h.llmCache != nil {
+		stats := h.llmCache.Stats()
+		fmt.Fprintf(&b, "\n*Кэш ответов* (с запуска): %d попаданий, %d промахов, %d записей, %d КБ\n",
+			stats.Hits, stats.Misses, stats.Entries, stats.Bytes/1024)
+	}
+
+	b.WriteString("\nВыгрузка: /usage\\_export")
+	h.sendMessage(userID, b.String())
+}
+
+// handleUsageExport отправляет расход CSV файлом: за день или за все дни (/usage_export all)
+func (h *Handler) handleUsageExport(userID int64, text string) {
+	if h.usage == nil {
+		h.sendMessage(userID, "📊 Учет расхода не включен.")
+		return
+	}
+	day, ok := h.usageDay(text)
+	if !ok {
+		h.sendMessage(userID, "Использование: /usage\\_export [ГГГГ-ММ-ДД|all]")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.usage.WriteCSV(&buf, day); err != nil {
+		log.Printf("usage export: %v", err)
+		h.sendMessage(userID, "❌ Не удалось выгрузить расход.")
+		return
+	}
+
+	name := "usage-all.csv"
+	if day != "" {
+		name = "usage-" + day + ".csv"
+	}
+	doc := tgbotapi.NewDocument(userID, tgbotapi.FileBytes{Name: name, Bytes: buf.Bytes()})
+	if _, err := h.bot.Send(doc); err != nil {
+		log.Printf("usage export: %v", err)
+	}
+}
+
+func featureName(feature string) string {
+	if name, ok := featureNames[feature]; ok {
+		return name
+	}
+	if feature == "" {
+		return "прочее"
+	}
+	return feature
+}
+
+func formatTotals(t usage.Totals) string {
+	return fmt.Sprintf("%d токенов (%d запросов), $%.4f", t.Tokens(), t.Requests, t.Cost)
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+
+	"viget-mvp/internal/models"
+)
+
+const (
+	// candidatesLimit — сколько исполнителей показывать автору задачи
+	candidatesLimit = 5
+	// candidateReasons — сколько причин совпадения показывать для каждого
+	candidateReasons = 4
+)
+
+// authorTasks возвращает задачи пользователя, новые первыми
+func (h *Handler) authorTasks(userID int64) []*models.TaskProfile {
+	author := models.TaskAuthor(userID)
+
+	var tasks []*models.TaskProfile
+	for _, task := range h.storage.ListTasks() {
+		if task.CreatedBy == author {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+	return tasks
+}
+
+func (h *Handler) handleCandidatesCommand(userID int64) {
+	tasks := h.authorTasks(userID)
+	switch len(tasks) {
+	case 0:
+		h.sendMessage(userID, "📭 У вас пока нет задач.\n\n➕ Создайте задачу: /create_task")
+	case 1:
+		h.handleCandidates(userID, tasks[0].ID)
+	default:
+		h.sendMessageWithKeyboard(userID, "📋 Для какой задачи подобрать исполнителей?", AuthorTasksKeyboard(tasks))
+	}
+}
+
+// handleCandidates показывает автору задачи лучших исполнителей для нее
+func (h *Handler) handleCandidates(userID int64, taskID string) {
+	task := h.storage.GetTask(taskID)
+	if task == nil {
+		h.sendMessage(userID, "❌ Задача не найдена.")
+		return
+	}
+	if task.CreatedBy != models.TaskAuthor(userID) {
+		h.sendMessage(userID, "❌ Исполнителей можно подбирать только для своих задач.")
+		return
+	}
+
+	matches, err := h.matcher.RecommendForTask(h.storage, task.ID, candidatesLimit)
+	if err != nil {
+		h.sendMessage(userID, "❌ Ошибка подбора исполнителей. Попробуйте позже.")
+		return
+	}
+
+	if len(matches) == 0 {
+		h.sendMessage(userID, fmt.Sprintf("😕 Для задачи «%s» пока нет подходящих исполнителей. Загляните позже — новые профили появляются постоянно.", escape(task.Title)))
+		return
+	}
+
+	h.sendMessage(userID, candidatesMessage(task, matches, h.storage.GetUserProfile))
+}
+
+// candidatesMessage перечисляет исполнителей; профиль, которого уже нет
+// (lookup вернул nil), пропускается без пробела в нумерации
+func candidatesMessage(task *models.TaskProfile, matches []models.MatchResult, lookup func(userID string) *models.UserProfile) string {
+	msg := fmt.Sprintf("👥 **Подходящие исполнители для «%s»:**\n\n", escape(task.Title))
+	n := 0
+	for _, match := range matches {
+		candidate := lookup(match.UserID)
+		if candidate == nil {
+			continue
+		}
+		n++
+
+		reasons := match.Reasons
+		if len(reasons) > candidateReasons {
+			reasons = reasons[:candidateReasons]
+		}
+
+		msg += fmt.Sprintf("%d. **%s** — совпадение %.0f%%\n", n, escape(candidate.Name), match.Score*100)
+		for _, reason := range reasons {
+			msg += "   " + escape(reason) + "\n"
+		}
+		msg += fmt.Sprintf("   ✉️ [Написать](tg://user?id=%d)\n\n", candidate.TelegramID)
+	}
+	return msg
+}
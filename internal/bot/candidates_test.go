@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"viget-mvp/internal/models"
+)
+
+func TestCandidatesMessage(t *testing.T) {
+	task := &models.TaskProfile{ID: "task_1", Title: "Бот_для_кафе"}
+	profiles := map[string]*models.UserProfile{
+		"2": {ID: "2", TelegramID: 2, Name: "anna_dev*"},
+		"4": {ID: "4", TelegramID: 4, Name: "Олег"},
+	}
+	matches := []models.MatchResult{
+		{UserID: "2", Score: 0.9, Reasons: []string{"✅ Go: уровень 4/3"}},
+		{UserID: "3", Score: 0.8}, // профиль удален
+		{UserID: "4", Score: 0.7},
+	}
+
+	msg := candidatesMessage(task, matches, func(userID string) *models.UserProfile { return profiles[userID] })
+
+	for _, want := range []string{`Бот\_для\_кафе`, `1. **anna\_dev\*** — совпадение 90%`, "2. **Олег** — совпадение 70%"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "3.") {
+		t.Errorf("numbering has a gap:\n%s", msg)
+	}
+}
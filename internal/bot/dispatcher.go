@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// queueSize — сколько обновлений в среднем может ждать на один обработчик;
+// при переполнении чтение обновлений из Telegram приостанавливается
+const queueSize = 64
+
+// dispatcher раздает обновления пользователей не более чем workers
+// обработчикам одновременно. У каждого пользователя своя очередь:
+// его обновления выполняются по порядку, а долгое обращение к модели
+// задерживает только его самого, пока свободен хотя бы один обработчик.
+type dispatcher struct {
+	handle func(tgbotapi.Update)
+	// slots ограничивает число одновременно обрабатываемых обновлений
+	slots chan struct{}
+	// pending ограничивает число принятых, но еще не обработанных обновлений
+	pending chan struct{}
+
+	mutex sync.Mutex
+	// queues — очереди пользователей, у которых есть необработанные обновления
+	queues map[int64][]tgbotapi.Update
+	wg     sync.WaitGroup
+}
+
+func newDispatcher(workers int, handle func(tgbotapi.Update)) *dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &dispatcher{
+		handle:  handle,
+		slots:   make(chan struct{}, workers),
+		pending: make(chan struct{}, workers*queueSize),
+		queues:  make(map[int64][]tgbotapi.Update),
+	}
+}
+
+// dispatch ставит обновление в очередь его пользователя. Если очереди не
+// было, для нее запускается горутина, которая разбирает очередь и завершается,
+// когда та опустеет.
+func (d *dispatcher) dispatch(update tgbotapi.Update) {
+	d.pending <- struct{}{}
+
+	userID := updateUserID(update)
+
+	d.mutex.Lock()
+	queue, running := d.queues[userID]
+	d.queues[userID] = append(queue, update)
+	if !running {
+		d.wg.Add(1)
+		go d.work(userID)
+	}
+	d.mutex.Unlock()
+}
+
+// stop дожидается обработки уже поставленных в очередь обновлений.
+// Вызывается после последнего dispatch.
+func (d *dispatcher) stop() {
+	d.wg.Wait()
+}
+
+func (d *dispatcher) work(userID int64) {
+	defer d.wg.Done()
+	for {
+		d.mutex.Lock()
+		queue := d.queues[userID]
+		if len(queue) == 0 {
+			delete(d.queues, userID)
+			d.mutex.Unlock()
+			return
+		}
+		update := queue[0]
+		d.queues[userID] = queue[1:]
+		d.mutex.Unlock()
+
+		d.slots <- struct{}{}
+		d.safeHandle(update)
+		<-d.slots
+		<-d.pending
+	}
+}
+
+// safeHandle не дает ошибке в обработке одного сообщения остановить обработчик
+func (d *dispatcher) safeHandle(update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic while handling update %d: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+	d.handle(update)
+}
+
+func updateUserID(update tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return update.Message.From.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		return update.CallbackQuery.From.ID
+	}
+	return 0
+}
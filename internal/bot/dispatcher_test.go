@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func message(userID int64, updateID int) tgbotapi.Update {
+	return tgbotapi.Update{
+		UpdateID: updateID,
+		Message:  &tgbotapi.Message{From: &tgbotapi.User{ID: userID}},
+	}
+}
+
+func TestDispatcherKeepsUserOrder(t *testing.T) {
+	const users, perUser, workers = 5, 100, 3
+
+	var mutex sync.Mutex
+	seen := make(map[int64][]int)
+	busy := make(map[int64]bool)
+	running, maxRunning := 0, 0
+
+	d := newDispatcher(workers, func(u tgbotapi.Update) {
+		userID := u.Message.From.ID
+
+		mutex.Lock()
+		if busy[userID] {
+			t.Errorf("user %d: two updates handled at once", userID)
+		}
+		busy[userID] = true
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(time.Microsecond)
+
+		mutex.Lock()
+		seen[userID] = append(seen[userID], u.UpdateID)
+		busy[userID] = false
+		running--
+		mutex.Unlock()
+	})
+
+	for n := 0; n < perUser; n++ {
+		for userID := int64(1); userID <= users; userID++ {
+			d.dispatch(message(userID, n))
+		}
+	}
+	d.stop()
+
+	for userID := int64(1); userID <= users; userID++ {
+		ids := seen[userID]
+		if len(ids) != perUser {
+			t.Fatalf("user %d: handled %d updates, want %d", userID, len(ids), perUser)
+		}
+		for n, id := range ids {
+			if id != n {
+				t.Fatalf("user %d: update %d handled at position %d", userID, id, n)
+			}
+		}
+	}
+	if maxRunning > workers {
+		t.Errorf("%d updates handled at once, limit is %d", maxRunning, workers)
+	}
+	if len(d.queues) != 0 {
+		t.Errorf("%d queues left after stop", len(d.queues))
+	}
+}
+
+func TestDispatcherSlowUserDoesNotBlockOthers(t *testing.T) {
+	release := make(chan struct{})
+	handled := make(chan int64, 1)
+
+	// Пользователи 1 и 3 раньше попадали в одну очередь из двух
+	d := newDispatcher(2, func(u tgbotapi.Update) {
+		if u.Message.From.ID == 1 {
+			<-release
+			return
+		}
+		handled <- u.Message.From.ID
+	})
+	defer d.stop()
+	defer close(release)
+
+	d.dispatch(message(1, 1))
+	d.dispatch(message(3, 2))
+
+	select {
+	case userID := <-handled:
+		if userID != 3 {
+			t.Errorf("handled user %d, want 3", userID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("user 3 waited for a slow update of user 1")
+	}
+}
@@ -2,7 +2,10 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 
@@ -11,17 +14,27 @@ import (
 	"viget-mvp/internal/matcher"
 	"viget-mvp/internal/models"
 	"viget-mvp/internal/profile"
+	"viget-mvp/internal/skills"
+	"viget-mvp/internal/usage"
 	"viget-mvp/internal/vibot"
+	"viget-mvp/pkg/gpt"
 )
 
 type Handler struct {
 	bot         *tgbotapi.BotAPI
-	storage     *profile.InMemoryStorage
+	storage     profile.Store
 	interviewer *vibot.Interviewer
 	matcher     *matcher.Matcher
+
+	// admins — кому доступны служебные команды, usage — учет расхода модели,
+	// llmCache — кэш ответов модели (nil — выключен), taxonomy — синонимы навыков
+	admins   map[int64]bool
+	usage    *usage.Tracker
+	llmCache *gpt.Cache
+	taxonomy *skills.Taxonomy
 }
 
-func NewHandler(bot *tgbotapi.BotAPI, storage *profile.InMemoryStorage,
+func NewHandler(bot *tgbotapi.BotAPI, storage profile.Store,
 	interviewer *vibot.Interviewer, matcher *matcher.Matcher) *Handler {
 	return &Handler{
 		bot:         bot,
@@ -31,18 +44,27 @@ func NewHandler(bot *tgbotapi.BotAPI, storage *profile.InMemoryStorage,
 	}
 }
 
-func (h *Handler) Start() {
+// Start читает обновления и обрабатывает их в workers параллельных обработчиках,
+// сохраняя порядок сообщений каждого пользователя
+func (h *Handler) Start(workers int) {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
 	updates := h.bot.GetUpdatesChan(u)
 
+	d := newDispatcher(workers, h.handleUpdate)
+	defer d.stop()
+
 	for update := range updates {
-		if update.Message != nil {
-			h.handleMessage(update.Message)
-		} else if update.CallbackQuery != nil {
-			h.handleCallback(update.CallbackQuery)
-		}
+		d.dispatch(update)
+	}
+}
+
+func (h *Handler) handleUpdate(update tgbotapi.Update) {
+	if update.Message != nil {
+		h.handleMessage(update.Message)
+	} else if update.CallbackQuery != nil {
+		h.handleCallback(update.CallbackQuery)
 	}
 }
 
@@ -61,10 +83,26 @@ func (h *Handler) handleMessage(message *tgbotapi.Message) {
 		h.handleTasks(userID)
 	case strings.HasPrefix(text, "/create_task"):
 		h.handleCreateTask(userID)
+	case strings.HasPrefix(text, "/candidates"):
+		h.handleCandidatesCommand(userID)
 	case strings.HasPrefix(text, "/help"):
 		h.handleHelp(userID)
 	case strings.HasPrefix(text, "/cancel"):
 		h.handleCancel(userID)
+	case strings.HasPrefix(text, "/back"):
+		h.handleBack(userID)
+	case strings.HasPrefix(text, "/edit"):
+		h.handleEditCommand(userID, text)
+	case strings.HasPrefix(text, "/usage_export") && h.isAdmin(userID):
+		h.handleUsageExport(userID, text)
+	case strings.HasPrefix(text, "/usage") && h.isAdmin(userID):
+		h.handleUsage(userID, text)
+	case strings.HasPrefix(text, "/skills") && h.isAdmin(userID):
+		h.handleSkills(userID)
+	case strings.HasPrefix(text, "/skill_add") && h.isAdmin(userID):
+		h.handleSkillAdd(userID, text)
+	case strings.HasPrefix(text, "/skill_merge") && h.isAdmin(userID):
+		h.handleSkillMerge(userID, text)
 	default:
 		// Если пользователь в процессе интервью
 		if h.interviewer.IsInInterview(userID) {
@@ -94,6 +132,10 @@ func (h *Handler) handleStart(userID int64) {
 • Создать задачу: /create_task`, profile.Name)
 	}
 
+	if h.interviewer.IsInInterview(userID) {
+		msg += "\n\n⏸️ У вас есть незавершенное интервью. Просто ответьте на последний вопрос или повторите его: /interview"
+	}
+
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("👤 Создать профиль", "interview"),
@@ -138,14 +180,9 @@ func (h *Handler) handleProfile(userID int64) {
 func (h *Handler) handleInterview(userID int64) {
 	// Проверяем, не находится ли пользователь уже в интервью
 	if h.interviewer.IsInInterview(userID) {
-		currentType := h.interviewer.GetInterviewType(userID)
-		var typeMsg string
-		if currentType == "profile" {
-			typeMsg = "создания профиля"
-		} else {
-			typeMsg = "создания задачи"
-		}
-		h.sendMessage(userID, fmt.Sprintf("⚠️ Вы уже проходите интервью для %s.\n\nИспользуйте /cancel для отмены.", typeMsg))
+		typeMsg := interviewTypeName(h.interviewer.GetInterviewType(userID))
+		h.sendMessage(userID, fmt.Sprintf("⚠️ Вы уже проходите интервью для %s. Продолжим с того места, где остановились.\n\nИспользуйте /cancel для отмены.", typeMsg))
+		h.sendQuestion(userID, h.interviewer.GetCurrentQuestion(userID))
 		return
 	}
 
@@ -155,21 +192,15 @@ func (h *Handler) handleInterview(userID int64) {
 		return
 	}
 
-	question := h.interviewer.GetCurrentQuestion(userID)
-	h.sendMessage(userID, question+"\n\n💡 Используйте /cancel для отмены интервью")
+	h.sendQuestion(userID, h.interviewer.GetCurrentQuestion(userID))
 }
 
 func (h *Handler) handleCreateTask(userID int64) {
 	// Проверяем, не находится ли пользователь уже в интервью
 	if h.interviewer.IsInInterview(userID) {
-		currentType := h.interviewer.GetInterviewType(userID)
-		var typeMsg string
-		if currentType == "profile" {
-			typeMsg = "создания профиля"
-		} else {
-			typeMsg = "создания задачи"
-		}
-		h.sendMessage(userID, fmt.Sprintf("⚠️ Вы уже проходите интервью для %s.\n\nИспользуйте /cancel для отмены.", typeMsg))
+		typeMsg := interviewTypeName(h.interviewer.GetInterviewType(userID))
+		h.sendMessage(userID, fmt.Sprintf("⚠️ Вы уже проходите интервью для %s. Продолжим с того места, где остановились.\n\nИспользуйте /cancel для отмены.", typeMsg))
+		h.sendQuestion(userID, h.interviewer.GetCurrentQuestion(userID))
 		return
 	}
 
@@ -179,8 +210,7 @@ func (h *Handler) handleCreateTask(userID int64) {
 		return
 	}
 
-	question := h.interviewer.GetCurrentQuestion(userID)
-	h.sendMessage(userID, question+"\n\n💡 Используйте /cancel для отмены")
+	h.sendQuestion(userID, h.interviewer.GetCurrentQuestion(userID))
 }
 
 func (h *Handler) handleCancel(userID int64) {
@@ -195,63 +225,111 @@ func (h *Handler) handleCancel(userID int64) {
 	// Удаляем сессию (добавим этот метод в interviewer)
 	h.interviewer.CancelInterview(userID)
 
-	var typeMsg string
-	if interviewType == "profile" {
-		typeMsg = "создания профиля"
-	} else {
-		typeMsg = "создания задачи"
-	}
-
-	h.sendMessage(userID, fmt.Sprintf("❌ Интервью для %s отменено.\n\n🔄 Используйте /start для возврата в главное меню.", typeMsg))
+	h.sendMessage(userID, fmt.Sprintf("❌ Интервью для %s отменено.\n\n🔄 Используйте /start для возврата в главное меню.", interviewTypeName(interviewType)))
 }
 
 func (h *Handler) handleInterviewAnswer(userID int64, answer string) {
 	interviewType := h.interviewer.GetInterviewType(userID)
 
-	nextQuestion, finished, err := h.interviewer.ProcessAnswer(userID, answer)
+	// В беседе следующий вопрос печатается по мере того, как его пишет модель
+	live := h.newLiveMessage(userID)
+	nextQuestion, finished, err := h.interviewer.ProcessAnswer(userID, answer, live.update)
+	h.handleInterviewStep(live, userID, interviewType, nextQuestion, finished, err)
+}
+
+// llmErrorMessage объясняет пользователю ошибку модели; для остальных ошибок — fallback.
+// Ответы уже сохранены, поэтому повторить можно любым сообщением.
+func llmErrorMessage(err error, fallback string) string {
+	log.Printf("llm error: %v", err)
+
+	switch {
+	case errors.Is(err, usage.ErrBudgetExceeded):
+		return "🚫 Дневной лимит обращений к сервису анализа исчерпан. Ваши ответы сохранены — продолжите завтра."
+	case errors.Is(err, gpt.ErrRateLimited):
+		return "⏳ Сервис анализа сейчас перегружен. Подождите минуту и отправьте любое сообщение, чтобы повторить."
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, gpt.ErrUnavailable):
+		return "⏳ Сервис анализа не ответил вовремя. Отправьте любое сообщение, чтобы повторить."
+	case errors.Is(err, gpt.ErrAuth), errors.Is(err, gpt.ErrQuota):
+		return "🔧 Сервис анализа временно недоступен. Ваши ответы сохранены — попробуйте позже."
+	case errors.Is(err, gpt.ErrInvalidJSON):
+		return "❌ Не удалось разобрать ответы. Отправьте любое сообщение, чтобы попробовать еще раз, или уточните ответы через /edit."
+	case errors.Is(err, gpt.ErrBadRequest):
+		return "❌ Не удалось обработать ответы. Попробуйте изменить ответ через /edit или начните заново."
+	case errors.Is(err, vibot.ErrNotSaved):
+		return "❌ Не удалось сохранить результат. Ваши ответы сохранены — отправьте любое сообщение, чтобы повторить."
+	}
+	return fallback
+}
+
+// handleInterviewStep показывает следующий вопрос или завершает интервью.
+// live — сообщение, в котором уже печатается ответ модели (nil — создается здесь).
+func (h *Handler) handleInterviewStep(live *liveMessage, userID int64, interviewType, nextQuestion string, finished bool, err error) {
+	if live == nil {
+		live = h.newLiveMessage(userID)
+	}
+	defer live.stop()
+
 	if err != nil {
-		h.sendMessage(userID, "❌ Ошибка обработки ответа. Попробуйте еще раз.")
+		live.finish(llmErrorMessage(err, "❌ Ошибка обработки ответа. Попробуйте еще раз."))
 		return
 	}
 
 	if finished {
 		switch interviewType {
 		case "profile":
-			// Извлекаем профиль и сохраняем
-			profile, err := h.interviewer.ExtractProfile(userID)
+			// Извлекаем профиль, показывая, какие разделы уже заполнены
+			status := extractionStatus("⏳ Составляю ваш профиль…", profileSections)
+			live.update(status(""))
+			profile, err := h.interviewer.ExtractProfile(userID, live.progress(status), h.storage.SaveUserProfile)
 			if err != nil {
-				h.sendMessage(userID, "❌ Ошибка создания профиля. Попробуйте позже.")
+				live.finish(llmErrorMessage(err, "❌ Ошибка создания профиля. Попробуйте позже."))
 				return
 			}
 
-			h.storage.SaveUserProfile(profile)
-			h.sendMessage(userID, "✅ Интервью завершено! Ваш профиль создан.\n\n🎯 Теперь вы можете искать задачи: /tasks")
+			live.finish(fmt.Sprintf(`✅ Интервью завершено! Ваш профиль создан.
+
+🏷️ **Имя:** %s
+🛠️ **Навыки:** %s
+💡 **Интересы:** %s
+🎯 **Цели:** %s
+
+🎯 Теперь вы можете искать задачи: /tasks`,
+				profile.Name,
+				h.formatSkills(profile.Skills),
+				strings.Join(profile.Interests, ", "),
+				strings.Join(profile.Goals, ", ")))
 
 		case "task":
 			// Извлекаем задачу и сохраняем
-			task, err := h.interviewer.ExtractTask(userID)
+			status := extractionStatus("⏳ Оформляю задачу…", taskSections)
+			live.update(status(""))
+			task, err := h.interviewer.ExtractTask(userID, live.progress(status), h.storage.SaveTask)
 			if err != nil {
-				h.sendMessage(userID, "❌ Ошибка создания задачи. Попробуйте позже.")
+				live.finish(llmErrorMessage(err, "❌ Ошибка создания задачи. Попробуйте позже."))
 				return
 			}
 
-			h.storage.SaveTask(task)
-
 			msg := fmt.Sprintf(`✅ Задача успешно создана!
 
 📋 **%s**
 💰 Бюджет: %d ₽
 ⏰ Дедлайн: %s
 
-🎯 Ваша задача добавлена в систему и скоро появится у подходящих исполнителей.`,
+🎯 Ваша задача добавлена в систему и скоро появится у подходящих исполнителей.
+👥 Посмотреть, кто из них подходит лучше всего: /candidates`,
 				task.Title,
 				task.Budget,
 				task.Deadline.Format("02.01.2006"))
 
-			h.sendMessage(userID, msg)
+			keyboard := CandidatesKeyboard(task.ID)
+			live.finishWithKeyboard(msg, &keyboard)
 		}
+	} else if live.posted() {
+		// Реплика интервьюера уже печаталась в этом сообщении — дописываем ее
+		live.finish(questionText(nextQuestion))
 	} else {
-		h.sendMessage(userID, nextQuestion+"\n\n💡 Используйте /cancel для отмены интервью")
+		live.stop()
+		h.sendQuestion(userID, nextQuestion)
 	}
 }
 
@@ -268,20 +346,37 @@ func (h *Handler) handleTasks(userID int64) {
 		return
 	}
 
-	matches := h.matcher.FindMatchingTasks(userProfile, tasks)
+	matches, err := h.matcher.RecommendForUser(h.storage, userProfile.ID, 5)
+	if err != nil {
+		h.sendMessage(userID, "❌ Ошибка подбора задач. Попробуйте позже.")
+		return
+	}
 
-	if len(matches) == 0 {
+	// Подходящих задач нет или их уже удалили после подбора
+	msg := tasksMessage(matches, h.storage.GetTask)
+	if msg == "" {
 		h.sendMessage(userID, "😕 Не найдено подходящих задач. Попробуйте обновить профиль: /interview")
 		return
 	}
 
-	msg := "🎯 **Рекомендованные задачи:**\n\n"
-	for i, match := range matches {
-		if i >= 5 { // Показываем только топ-5
+	h.sendMessage(userID, msg)
+}
+
+// tasksMessage перечисляет рекомендованные задачи (не больше 5); задача, которой
+// уже нет (lookup вернул nil), пропускается. Пустая строка — показывать нечего.
+func tasksMessage(matches []models.MatchResult, lookup func(taskID string) *models.TaskProfile) string {
+	msg := ""
+	shown := 0
+	for _, match := range matches {
+		if shown >= 5 { // Показываем только топ-5
 			break
 		}
 
-		task := h.storage.GetTask(match.TaskID)
+		task := lookup(match.TaskID)
+		if task == nil {
+			continue
+		}
+		shown++
 		msg += fmt.Sprintf(`📋 **%s**
 💰 %d ₽
 🎯 Совпадение: %.0f%%
@@ -289,10 +384,11 @@ func (h *Handler) handleTasks(userID int64) {
 
 `, task.Title, task.Budget, match.Score*100, task.Deadline.Format("02.01"))
 	}
+	if shown == 0 {
+		return ""
+	}
 
-	msg += "\n💡 Для получения полной информации о задаче свяжитесь с @monforje"
-
-	h.sendMessage(userID, msg)
+	return "🎯 **Рекомендованные задачи:**\n\n" + msg + "\n💡 Для получения полной информации о задаче свяжитесь с @monforje"
 }
 
 func (h *Handler) handleHelp(userID int64) {
@@ -304,6 +400,9 @@ func (h *Handler) handleHelp(userID int64) {
 /interview - Пройти интервью для создания профиля
 /tasks - Найти подходящие задачи
 /create_task - Создать задачу для исполнителей
+/candidates - Подходящие исполнители для ваших задач
+/back - Вернуться к предыдущему вопросу интервью
+/edit N - Изменить ответ на вопрос N
 /cancel - Отменить текущее интервью
 /help - Эта справка
 
@@ -333,6 +432,36 @@ func (h *Handler) handleCallback(callback *tgbotapi.CallbackQuery) {
 		h.handleProfile(userID)
 	case "create_task":
 		h.handleCreateTask(userID)
+	case "session_resume":
+		h.handleSessionResume(userID)
+	case "session_discard":
+		h.handleCancel(userID)
+	case "interview_back":
+		h.handleBack(userID)
+	case "interview_edit":
+		h.handleEditMenu(userID)
+	case "choice_done":
+		h.handleChoiceDone(userID)
+	default:
+		prefix, arg, _ := strings.Cut(data, ":")
+		if prefix == "candidates" {
+			h.handleCandidates(userID, arg)
+			return
+		}
+
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return
+		}
+
+		switch prefix {
+		case "edit_answer":
+			h.handleEditAnswer(userID, n)
+		case "choice":
+			h.handleChoice(userID, n)
+		case "toggle":
+			h.handleToggle(userID, n, callback.Message)
+		}
 	}
 }
 
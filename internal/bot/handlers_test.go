@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+func TestTasksMessageSkipsDeletedTasks(t *testing.T) {
+	deadline := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	tasks := map[string]*models.TaskProfile{
+		"task_1": {ID: "task_1", Title: "Бот для кафе", Budget: 30000, Deadline: deadline},
+		"task_3": {ID: "task_3", Title: "Лендинг", Budget: 15000, Deadline: deadline},
+	}
+	matches := []models.MatchResult{
+		{TaskID: "task_1", Score: 0.9},
+		{TaskID: "task_2", Score: 0.8}, // задачу удалили после подбора
+		{TaskID: "task_3", Score: 0.7},
+	}
+
+	msg := tasksMessage(matches, func(taskID string) *models.TaskProfile { return tasks[taskID] })
+
+	for _, want := range []string{"📋 **Бот для кафе**", "🎯 Совпадение: 90%", "📋 **Лендинг**", "⏰ До 15.03"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg)
+		}
+	}
+	if strings.Count(msg, "📋") != 2 {
+		t.Errorf("want 2 tasks:\n%s", msg)
+	}
+
+	if msg := tasksMessage(matches[1:2], func(string) *models.TaskProfile { return nil }); msg != "" {
+		t.Errorf("all tasks deleted: message = %q, want empty", msg)
+	}
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// sendQuestion отправляет вопрос интервью с вариантами ответа и кнопками навигации
+func (h *Handler) sendQuestion(userID int64, question string) {
+	text := questionText(question)
+
+	template, selected, _ := h.interviewer.CurrentTemplate(userID)
+	canGoBack := len(h.interviewer.AnsweredQuestions(userID)) > 0
+
+	keyboard := QuestionKeyboard(template, selected, canGoBack)
+	if len(keyboard.InlineKeyboard) == 0 {
+		h.sendMessage(userID, text)
+		return
+	}
+	h.sendMessageWithKeyboard(userID, text, keyboard)
+}
+
+// questionText добавляет к вопросу подсказку об отмене
+func questionText(question string) string {
+	return question + "\n\n💡 Используйте /cancel для отмены интервью"
+}
+
+func (h *Handler) handleChoice(userID int64, index int) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "❌ Вы не проходите интервью.")
+		return
+	}
+
+	interviewType := h.interviewer.GetInterviewType(userID)
+	nextQuestion, finished, err := h.interviewer.SelectOption(userID, index)
+	h.handleInterviewStep(nil, userID, interviewType, nextQuestion, finished, err)
+}
+
+// handleToggle отмечает вариант и перерисовывает клавиатуру под тем же сообщением
+func (h *Handler) handleToggle(userID int64, index int, message *tgbotapi.Message) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "❌ Вы не проходите интервью.")
+		return
+	}
+
+	selected, err := h.interviewer.ToggleOption(userID, index)
+	if err != nil {
+		// Кнопка от старого вопроса — просто показываем актуальный
+		h.sendQuestion(userID, h.interviewer.GetCurrentQuestion(userID))
+		return
+	}
+	if message == nil {
+		return
+	}
+
+	template, _, _ := h.interviewer.CurrentTemplate(userID)
+	canGoBack := len(h.interviewer.AnsweredQuestions(userID)) > 0
+	edit := tgbotapi.NewEditMessageReplyMarkup(message.Chat.ID, message.MessageID,
+		QuestionKeyboard(template, selected, canGoBack))
+	h.bot.Send(edit)
+}
+
+func (h *Handler) handleChoiceDone(userID int64) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "❌ Вы не проходите интервью.")
+		return
+	}
+
+	interviewType := h.interviewer.GetInterviewType(userID)
+	nextQuestion, finished, err := h.interviewer.SubmitSelection(userID)
+	h.handleInterviewStep(nil, userID, interviewType, nextQuestion, finished, err)
+}
+
+func (h *Handler) handleBack(userID int64) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "❌ Вы не проходите интервью.")
+		return
+	}
+
+	question, err := h.interviewer.GoBack(userID)
+	if err != nil {
+		h.sendMessage(userID, "❌ Не удалось вернуться к предыдущему вопросу.")
+		return
+	}
+	h.sendQuestion(userID, question)
+}
+
+func (h *Handler) handleEditMenu(userID int64) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "❌ Вы не проходите интервью.")
+		return
+	}
+
+	answered := h.interviewer.AnsweredQuestions(userID)
+	if len(answered) == 0 {
+		h.sendMessage(userID, "⚠️ Вы еще не ответили ни на один вопрос.")
+		return
+	}
+
+	h.sendMessageWithKeyboard(userID, "✏️ Какой ответ хотите изменить?", EditAnswersKeyboard(answered))
+}
+
+func (h *Handler) handleEditAnswer(userID int64, step int) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "❌ Вы не проходите интервью.")
+		return
+	}
+
+	question, err := h.interviewer.EditAnswer(userID, step)
+	if err != nil {
+		h.sendMessage(userID, fmt.Sprintf("❌ Нет ответа на вопрос %d.", step+1))
+		return
+	}
+	h.sendQuestion(userID, question)
+}
+
+// handleEditCommand обрабатывает "/edit N"; без номера показывает список ответов
+func (h *Handler) handleEditCommand(userID int64, text string) {
+	arg := strings.TrimSpace(strings.TrimPrefix(text, "/edit"))
+	if arg == "" {
+		h.handleEditMenu(userID)
+		return
+	}
+
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 {
+		h.sendMessage(userID, "⚠️ Укажите номер вопроса, например: /edit 2")
+		return
+	}
+	h.handleEditAnswer(userID, n-1)
+}
@@ -1,6 +1,13 @@
 package bot
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/vibot"
+)
 
 func MainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.NewReplyKeyboard(
@@ -26,3 +33,84 @@ func BackKeyboard() tgbotapi.ReplyKeyboardMarkup {
 func RemoveKeyboard() tgbotapi.ReplyKeyboardRemove {
 	return tgbotapi.ReplyKeyboardRemove{RemoveKeyboard: true}
 }
+
+// QuestionKeyboard — варианты ответа на вопрос (если есть) и кнопки навигации.
+// Для множественного выбора отмеченные варианты помечаются ✅, ответ отправляется кнопкой «Готово».
+func QuestionKeyboard(q vibot.QuestionTemplate, selected []int, canGoBack bool) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	if q.Type == "choice" {
+		checked := make(map[int]bool, len(selected))
+		for _, idx := range selected {
+			checked[idx] = true
+		}
+
+		for i, option := range q.Options {
+			label := option.Label
+			data := fmt.Sprintf("choice:%d", i)
+			if q.Multiple {
+				data = fmt.Sprintf("toggle:%d", i)
+				if checked[i] {
+					label = "✅ " + label
+				}
+			}
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(label, data),
+			))
+		}
+
+		if q.Multiple {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("✔️ Готово", "choice_done"),
+			))
+		}
+	}
+
+	if canGoBack {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "interview_back"),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Изменить ответ", "interview_edit"),
+		))
+	}
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func EditAnswersKeyboard(answered []vibot.AnsweredQuestion) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, a := range answered {
+		label := fmt.Sprintf("%d. %s", a.Step+1, truncate(a.Answer, 30))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("edit_answer:%d", a.Step)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// CandidatesKeyboard — кнопка подбора исполнителей под сообщением о задаче
+func CandidatesKeyboard(taskID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👥 Подходящие исполнители", "candidates:"+taskID),
+		),
+	)
+}
+
+// AuthorTasksKeyboard — выбор задачи, для которой подобрать исполнителей
+func AuthorTasksKeyboard(tasks []*models.TaskProfile) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, task := range tasks {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 "+truncate(task.Title, 40), "candidates:"+task.ID),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// editInterval — не чаще этого сообщение правится по ходу генерации (лимиты Telegram)
+	editInterval = time.Second
+	// typingInterval — как часто повторять "печатает…": Telegram показывает его ~5 секунд
+	typingInterval = 4 * time.Second
+)
+
+// liveMessage показывает ответ модели по мере генерации: пока модель думает,
+// в чате висит "печатает…", с первым текстом появляется сообщение, которое
+// затем правится. Методы можно вызывать из разных горутин.
+type liveMessage struct {
+	h      *Handler
+	userID int64
+
+	mutex     sync.Mutex
+	messageID int // 0 — сообщение еще не отправлено
+	text      string
+	pending   string
+	lastEdit  time.Time
+	done      bool
+
+	stopTyping chan struct{}
+}
+
+// newLiveMessage включает "печатает…" до вызова finish или stop
+func (h *Handler) newLiveMessage(userID int64) *liveMessage {
+	m := &liveMessage{h: h, userID: userID, stopTyping: make(chan struct{})}
+	go m.typing()
+	return m
+}
+
+func (m *liveMessage) typing() {
+	ticker := time.NewTicker(typingInterval)
+	defer ticker.Stop()
+	for {
+		m.h.bot.Request(tgbotapi.NewChatAction(m.userID, tgbotapi.ChatTyping))
+		select {
+		case <-m.stopTyping:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update показывает текст, полученный к этому моменту (подходит как gpt.ProgressFunc)
+func (m *liveMessage) update(text string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.done || text == "" {
+		return
+	}
+	m.pending = text
+	if time.Since(m.lastEdit) < editInterval {
+		return
+	}
+	m.flush()
+}
+
+// progress возвращает gpt.ProgressFunc, показывающую не сам ответ, а status(ответ)
+func (m *liveMessage) progress(status func(text string) string) func(string) {
+	return func(text string) {
+		m.update(status(text))
+	}
+}
+
+// flush отправляет или правит сообщение; вызывается под mutex
+func (m *liveMessage) flush() {
+	if m.pending == m.text {
+		return
+	}
+	m.lastEdit = time.Now()
+
+	// Частичный текст отправляем без разметки: незакрытая * сломала бы Markdown
+	if m.messageID == 0 {
+		sent, err := m.h.bot.Send(tgbotapi.NewMessage(m.userID, m.pending))
+		if err != nil {
+			log.Printf("live message for user %d: %v", m.userID, err)
+			return
+		}
+		m.messageID = sent.MessageID
+	} else if _, err := m.h.bot.Request(tgbotapi.NewEditMessageText(m.userID, m.messageID, m.pending)); err != nil {
+		log.Printf("live message for user %d: %v", m.userID, err)
+		return
+	}
+	m.text = m.pending
+}
+
+// posted сообщает, было ли уже отправлено сообщение
+func (m *liveMessage) posted() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.messageID != 0
+}
+
+// finish заменяет промежуточный текст окончательным (Markdown) или,
+// если сообщения еще нет, отправляет его
+func (m *liveMessage) finish(text string) {
+	m.finishWithKeyboard(text, nil)
+}
+
+// finishWithKeyboard — finish с кнопками под сообщением (nil — без кнопок)
+func (m *liveMessage) finishWithKeyboard(text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	m.stop()
+
+	m.mutex.Lock()
+	messageID := m.messageID
+	m.mutex.Unlock()
+
+	if messageID == 0 {
+		if keyboard != nil {
+			m.h.sendMessageWithKeyboard(m.userID, text, *keyboard)
+		} else {
+			m.h.sendMessage(m.userID, text)
+		}
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(m.userID, messageID, text)
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	edit.ReplyMarkup = keyboard
+	if _, err := m.h.bot.Request(edit); err != nil {
+		log.Printf("live message for user %d: %v", m.userID, err)
+		// Разметка не разобралась — показываем как есть
+		plain := tgbotapi.NewEditMessageText(m.userID, messageID, text)
+		plain.ReplyMarkup = keyboard
+		m.h.bot.Request(plain)
+	}
+}
+
+// stop выключает "печатает…" и дальнейшие правки; повторный вызов ничего не делает
+func (m *liveMessage) stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.done {
+		m.done = true
+		close(m.stopTyping)
+	}
+}
+
+// extractionSection — поле JSON ответа модели и его название для пользователя
+type extractionSection struct {
+	key   string
+	label string
+}
+
+var (
+	profileSections = []extractionSection{
+		{`"name"`, "имя"},
+		{`"skills"`, "навыки"},
+		{`"soft_skills"`, "личные качества"},
+		{`"interests"`, "интересы"},
+		{`"goals"`, "цели"},
+		{`"experience"`, "опыт работы"},
+	}
+	taskSections = []extractionSection{
+		{`"title"`, "название"},
+		{`"description"`, "описание"},
+		{`"required_skills"`, "навыки исполнителя"},
+		{`"budget"`, "бюджет"},
+		{`"deadline_days"`, "срок"},
+	}
+)
+
+// extractionStatus описывает, какие разделы модель уже заполнила в частичном JSON
+func extractionStatus(title string, sections []extractionSection) func(string) string {
+	return func(partial string) string {
+		var b strings.Builder
+		b.WriteString(title + "\n")
+		for _, s := range sections {
+			mark := "▫️"
+			if strings.Contains(partial, s.key) {
+				mark = "✅"
+			}
+			b.WriteString("\n" + mark + " " + s.label)
+		}
+		return b.String()
+	}
+}
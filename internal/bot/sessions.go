@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// StartSessionSweeper периодически отменяет брошенные интервью
+// и напоминает о тех, что скоро истекут.
+func (h *Handler) StartSessionSweeper(interval, remindAfter time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			h.sweepSessions(now, remindAfter)
+		}
+	}()
+}
+
+func (h *Handler) sweepSessions(now time.Time, remindAfter time.Duration) {
+	remind, expired := h.interviewer.Sweep(now, remindAfter)
+
+	for _, session := range remind {
+		step, total := h.interviewer.Progress(session)
+		msg := fmt.Sprintf("⏰ Вы остановились на вопросе %d/%d в интервью для %s. Продолжим?",
+			step, total, interviewTypeName(session.Type))
+
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("▶️ Продолжить", "session_resume"),
+				tgbotapi.NewInlineKeyboardButtonData("🗑️ Отменить", "session_discard"),
+			),
+		)
+		h.sendMessageWithKeyboard(session.UserID, msg, keyboard)
+	}
+
+	for _, session := range expired {
+		h.sendMessage(session.UserID, fmt.Sprintf("⌛ Интервью для %s отменено из-за неактивности.\n\n🔄 Начать заново: /start",
+			interviewTypeName(session.Type)))
+	}
+}
+
+func (h *Handler) handleSessionResume(userID int64) {
+	if !h.interviewer.IsInInterview(userID) {
+		h.sendMessage(userID, "⌛ Интервью уже завершено или отменено.\n\n🔄 Используйте /start для возврата в главное меню.")
+		return
+	}
+
+	h.sendQuestion(userID, h.interviewer.GetCurrentQuestion(userID))
+}
+
+func interviewTypeName(interviewType string) string {
+	if interviewType == "profile" {
+		return "создания профиля"
+	}
+	return "создания задачи"
+}
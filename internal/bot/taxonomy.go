@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"viget-mvp/internal/skills"
+)
+
+// unknownSkillsLimit — сколько незнакомых навыков показывать в /skills
+const unknownSkillsLimit = 20
+
+// SetTaxonomy включает команды /skills, /skill_add и /skill_merge
+func (h *Handler) SetTaxonomy(taxonomy *skills.Taxonomy) {
+	h.taxonomy = taxonomy
+}
+
+// handleSkills показывает навыки, которых нет в таксономии, — самые частые первыми
+func (h *Handler) handleSkills(userID int64) {
+	if h.taxonomy == nil {
+		h.sendMessage(userID, "🧩 Таксономия навыков не подключена.")
+		return
+	}
+
+	unknown := h.taxonomy.Unknown()
+	if len(unknown) == 0 {
+		h.sendMessage(userID, "✅ Незнакомых навыков нет.")
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🧩 *Незнакомые навыки* (%d):\n\n", len(unknown))
+	for i, skill := range unknown {
+		if i >= unknownSkillsLimit {
+			fmt.Fprintf(&b, "… и еще %d\n", len(unknown)-unknownSkillsLimit)
+			break
+		}
+		fmt.Fprintf(&b, "• %s — %d упом., впервые %s\n", escape(skill.Name), skill.Count, skill.FirstSeen.Format("02.01"))
+	}
+	b.WriteString("\n/skill\\_add Навык — добавить как новый навык")
+	b.WriteString("\n/skill\\_merge Синоним = Навык — считать синонимом известного навыка")
+	h.sendMessage(userID, b.String())
+}
+
+// handleSkillAdd добавляет незнакомый навык в таксономию как есть: /skill_add Airflow
+func (h *Handler) handleSkillAdd(userID int64, text string) {
+	if h.taxonomy == nil {
+		h.sendMessage(userID, "🧩 Таксономия навыков не подключена.")
+		return
+	}
+
+	name := commandArgs(text)
+	if name == "" {
+		h.sendMessage(userID, "Использование: /skill\\_add Навык")
+		return
+	}
+	if canonical, known := h.taxonomy.Canonical(name); known {
+		h.sendMessage(userID, fmt.Sprintf("ℹ️ Навык уже известен как «%s».", escape(canonical)))
+		return
+	}
+
+	canonical, err := h.taxonomy.Add(name)
+	if err != nil {
+		log.Printf("skill add %q: %v", name, err)
+		h.sendMessage(userID, "❌ Не удалось добавить навык.")
+		return
+	}
+	h.sendMessage(userID, fmt.Sprintf("✅ Навык «%s» добавлен.", escape(canonical)))
+}
+
+// handleSkillMerge объявляет написание синонимом навыка: /skill_merge Vue3 = Vue.
+// Сохраненные профили и задачи не переписываются: подбор сравнивает
+// навыки по каноническим именам и сразу учтет синоним.
+func (h *Handler) handleSkillMerge(userID int64, text string) {
+	if h.taxonomy == nil {
+		h.sendMessage(userID, "🧩 Таксономия навыков не подключена.")
+		return
+	}
+
+	alias, skill, ok := strings.Cut(commandArgs(text), "=")
+	alias, skill = strings.TrimSpace(alias), strings.TrimSpace(skill)
+	if !ok || alias == "" || skill == "" {
+		h.sendMessage(userID, "Использование: /skill\\_merge Синоним = Навык")
+		return
+	}
+	current, _ := h.taxonomy.Canonical(alias)
+	target, known := h.taxonomy.Canonical(skill)
+	if current == target {
+		h.sendMessage(userID, fmt.Sprintf("ℹ️ «%s» уже означает «%s».", escape(alias), escape(target)))
+		return
+	}
+
+	canonical, err := h.taxonomy.Merge(alias, skill)
+	if err != nil {
+		log.Printf("skill merge %q -> %q: %v", alias, skill, err)
+		h.sendMessage(userID, "❌ Не удалось объединить навыки.")
+		return
+	}
+
+	msg := fmt.Sprintf("✅ «%s» теперь синоним навыка «%s».", escape(alias), escape(canonical))
+	if !known {
+		msg += "\nℹ️ Навыка не было в таксономии — он добавлен как новый."
+	}
+	h.sendMessage(userID, msg)
+}
+
+// commandArgs возвращает текст после команды
+func commandArgs(text string) string {
+	_, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	return strings.TrimSpace(args)
+}
+
+func escape(s string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, s)
+}
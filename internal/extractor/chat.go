@@ -0,0 +1,86 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/pkg/gpt"
+)
+
+// FeatureChatInterview — беседа интервьюера с пользователем (gpt.WithFeature)
+const FeatureChatInterview = "chat_interview"
+
+// chatDoneMarker — метка, которой интервьюер сообщает, что информации достаточно
+const chatDoneMarker = "[[DONE]]"
+
+// ChatReply — очередная реплика интервьюера
+type ChatReply struct {
+	Message string
+	// Done — интервьюер собрал достаточно информации
+	Done bool
+	// Tokens — токены, потраченные на запрос, по данным провайдера
+	// (если провайдер их не сообщил — оценка)
+	Tokens int
+}
+
+// Converse продолжает беседу для профиля: отправляет модели системный промпт
+// и историю transcript и возвращает ее следующую реплику. maxTurns сообщает
+// интервьюеру, сколько вопросов он может задать. Реплику по мере генерации
+// можно получать через gpt.WithProgress.
+func (e *Extractor) Converse(ctx context.Context, sessionKey string, maxTurns int, transcript []models.ChatMessage) (*ChatReply, error) {
+	ctx = gpt.WithFeature(ctx, FeatureChatInterview)
+	if progress := gpt.ProgressFrom(ctx); progress != nil {
+		// Метку завершения (и ее начало, пока она пишется) пользователю не показываем
+		ctx = gpt.WithProgress(ctx, func(text string) {
+			if n := strings.Index(text, "[["); n >= 0 {
+				text = text[:n]
+			}
+			progress(strings.TrimSpace(text))
+		})
+	}
+
+	version, err := e.prompts.Select(PromptProfileChat, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	system, err := e.prompts.Render(PromptProfileChat, version, promptData{MaxTurns: maxTurns})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []gpt.Message{{Role: gpt.RoleSystem, Content: system}}
+	for _, m := range transcript {
+		content := m.Content
+		if m.Role == gpt.RoleUser {
+			content = UserInput(content).String()
+		}
+		messages = append(messages, gpt.Message{Role: m.Role, Content: content})
+	}
+
+	var used gpt.Usage
+	response, err := gpt.Chat(gpt.WithUsage(ctx, &used), e.gptClient, messages)
+	if err != nil {
+		return nil, fmt.Errorf("prompt %s/%s: %w", PromptProfileChat, version, err)
+	}
+
+	reply := &ChatReply{
+		Message: strings.TrimSpace(strings.ReplaceAll(response, chatDoneMarker, "")),
+		Done:    strings.Contains(response, chatDoneMarker),
+	}
+	if !reply.Done && reply.Message == "" {
+		return nil, fmt.Errorf("prompt %s/%s: empty reply", PromptProfileChat, version)
+	}
+
+	reply.Tokens = used.TotalTokens
+	if reply.Tokens == 0 {
+		// LLM, который не сообщает расход
+		var prompt strings.Builder
+		for _, m := range messages {
+			prompt.WriteString(m.Content)
+		}
+		reply.Tokens = gpt.EstimateUsage(prompt.String(), response).TotalTokens
+	}
+	return reply, nil
+}
@@ -0,0 +1,57 @@
+package extractor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/pkg/gpt"
+)
+
+func TestConverseReportsProviderUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "Какими технологиями вы владеете?"}}],
+			"usage": {"prompt_tokens": 900, "completion_tokens": 100, "total_tokens": 1000}
+		}`))
+	}))
+	defer server.Close()
+
+	e := NewExtractor(gpt.NewOpenAIClient("", server.URL, "test"))
+	transcript := []models.ChatMessage{
+		{Role: gpt.RoleAssistant, Content: "Как вас зовут?"},
+		{Role: gpt.RoleUser, Content: "Анна"},
+	}
+
+	reply, err := e.Converse(context.Background(), "1:1", 10, transcript)
+	if err != nil {
+		t.Fatalf("Converse: %v", err)
+	}
+	if reply.Tokens != 1000 {
+		t.Errorf("Tokens = %d, want 1000 reported by the provider", reply.Tokens)
+	}
+}
+
+func TestConverseEstimatesUsage(t *testing.T) {
+	e := NewExtractor(llmFunc(func(context.Context, string) (string, error) {
+		return "Какими технологиями вы владеете?", nil
+	}))
+
+	reply, err := e.Converse(context.Background(), "1:1", 10, nil)
+	if err != nil {
+		t.Fatalf("Converse: %v", err)
+	}
+	if reply.Tokens == 0 {
+		t.Error("Tokens = 0 for an LLM that does not report usage, want an estimate")
+	}
+}
+
+// llmFunc — LLM без учета расхода
+type llmFunc func(ctx context.Context, prompt string) (string, error)
+
+func (f llmFunc) SendRequestContext(ctx context.Context, prompt string) (string, error) {
+	return f(ctx, prompt)
+}
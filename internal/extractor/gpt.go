@@ -1,24 +1,110 @@
 package extractor
 
 import (
-	"strings"
+	"context"
+	"fmt"
+
 	"viget-mvp/pkg/gpt"
 )
 
+// Extractor — единая точка извлечения данных моделью: владеет промптами,
+// схемами ответов и их разбором. Используется интервью и годится для
+// других источников (API, массовый импорт).
 type Extractor struct {
-	gptClient *gpt.Client
+	gptClient gpt.LLM
+	prompts   *Registry
+}
+
+// Функции, на которые учитывается расход токенов (gpt.WithFeature)
+const (
+	FeatureAnswerAnalysis    = "answer_analysis"
+	FeatureProfileExtraction = "profile_extraction"
+	FeatureTaskExtraction    = "task_extraction"
+)
+
+// Answer — вопрос интервью и ответ на него
+type Answer struct {
+	Question string
+	Answer   string
+}
+
+func NewExtractor(gptClient gpt.LLM) *Extractor {
+	return &Extractor{gptClient: gptClient, prompts: DefaultRegistry()}
 }
 
-func NewExtractor(gptClient *gpt.Client) *Extractor {
-	return &Extractor{gptClient: gptClient}
+// Prompts возвращает реестр промптов, например чтобы добавить версии или эксперименты
+func (e *Extractor) Prompts() *Registry {
+	return e.prompts
 }
 
-func (e *Extractor) ExtractProfile(userAnswer string) (string, error) {
-	prompt := strings.Replace(ProfilePrompt, "{{user_answer}}", userAnswer, 1)
-	return e.gptClient.SendRequest(prompt)
+// ExtractProfile извлекает профиль из ответов интервью. sessionKey определяет
+// версию промпта в эксперименте; она записывается в ProfileData.PromptVersion.
+func (e *Extractor) ExtractProfile(ctx context.Context, sessionKey string, answers []Answer) (*ProfileData, error) {
+	ctx = gpt.WithFeature(ctx, FeatureProfileExtraction)
+	version, data, err := e.complete(ctx, PromptProfile, sessionKey, newPromptData(answers), profileSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := DecodeProfile(data)
+	if err != nil {
+		return nil, err
+	}
+	profile.PromptVersion = PromptProfile + "/" + version
+	return profile, nil
 }
 
-func (e *Extractor) ExtractTask(taskDescription string) (string, error) {
-	prompt := strings.Replace(TaskPrompt, "{{task_description}}", taskDescription, 1)
-	return e.gptClient.SendRequest(prompt)
+// ExtractTask извлекает задачу из ответов интервью
+func (e *Extractor) ExtractTask(ctx context.Context, sessionKey string, answers []Answer) (*TaskData, error) {
+	ctx = gpt.WithFeature(ctx, FeatureTaskExtraction)
+	version, data, err := e.complete(ctx, PromptTask, sessionKey, newPromptData(answers), taskSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := DecodeTask(data)
+	if err != nil {
+		return nil, err
+	}
+	task.PromptVersion = PromptTask + "/" + version
+	return task, nil
+}
+
+// AnalyzeAnswer разбирает отдельный ответ интервью interviewType ("profile" или "task")
+// и возвращает контекст для адаптации следующих вопросов
+func (e *Extractor) AnalyzeAnswer(ctx context.Context, sessionKey, interviewType, answer string) (map[string]interface{}, error) {
+	var name string
+	var schema *gpt.Schema
+	switch interviewType {
+	case "profile":
+		name, schema = PromptProfileAnalysis, profileAnalysisSchema
+	case "task":
+		name, schema = PromptTaskAnalysis, taskAnalysisSchema
+	default:
+		return nil, fmt.Errorf("unsupported interview type: %s", interviewType)
+	}
+
+	ctx = gpt.WithFeature(ctx, FeatureAnswerAnalysis)
+	_, data, err := e.complete(ctx, name, sessionKey, promptData{Answer: UserInput(answer)}, schema)
+	return data, err
+}
+
+// complete выбирает версию промпта, заполняет его и запрашивает JSON по схеме
+func (e *Extractor) complete(ctx context.Context, name, sessionKey string, data promptData, schema *gpt.Schema) (string, map[string]interface{}, error) {
+	version, err := e.prompts.Select(name, sessionKey)
+	if err != nil {
+		return "", nil, err
+	}
+	prompt, err := e.prompts.Render(name, version, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Одинаковые данные в той же версии промпта можно отвечать из кэша
+	ctx = gpt.WithPromptVersion(ctx, name+"/"+version)
+	result, err := gpt.CompleteJSON(ctx, e.gptClient, prompt, schema, gpt.DefaultRepairs)
+	if err != nil {
+		return "", nil, fmt.Errorf("prompt %s/%s: %w", name, version, err)
+	}
+	return version, result, nil
 }
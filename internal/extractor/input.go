@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxInputLength — больше этого ответ пользователя в промпт не попадает
+const maxInputLength = 4000
+
+// UserInput — текст от пользователя. В шаблоне промпта печатается экранированным,
+// чтобы ответ не мог закрыть <answer> и выдать себя за инструкции.
+type UserInput string
+
+var inputReplacer = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+func (u UserInput) String() string {
+	text := strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, string(u))
+
+	if utf8.RuneCountInString(text) > maxInputLength {
+		text = string([]rune(text)[:maxInputLength]) + "…"
+	}
+	return inputReplacer.Replace(text)
+}
+
+// promptData — данные для шаблонов промптов
+type promptData struct {
+	Answer  UserInput
+	Answers []promptAnswer
+	// MaxTurns — сколько вопросов может задать интервьюер в беседе
+	MaxTurns int
+}
+
+type promptAnswer struct {
+	Question UserInput
+	Answer   UserInput
+}
+
+func newPromptData(answers []Answer) promptData {
+	data := promptData{Answers: make([]promptAnswer, len(answers))}
+	for n, a := range answers {
+		data.Answers[n] = promptAnswer{Question: UserInput(a.Question), Answer: UserInput(a.Answer)}
+	}
+	return data
+}
@@ -1,9 +1,39 @@
 package extractor
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"viget-mvp/pkg/gpt"
+)
 
 func ParseJSONResponse(response string) (map[string]interface{}, error) {
 	var result map[string]interface{}
-	err := json.Unmarshal([]byte(response), &result)
+	err := json.Unmarshal([]byte(gpt.ExtractJSON(response)), &result)
 	return result, err
 }
+
+// DecodeProfile переводит проверенный по схеме ответ модели в ProfileData
+func DecodeProfile(data map[string]interface{}) (*ProfileData, error) {
+	var profile ProfileData
+	if err := decode(data, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// DecodeTask переводит проверенный по схеме ответ модели в TaskData
+func DecodeTask(data map[string]interface{}) (*TaskData, error) {
+	var task TaskData
+	if err := decode(data, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func decode(data map[string]interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,229 @@
+package extractor
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Имена промптов
+const (
+	PromptProfile         = "profile"
+	PromptTask            = "task"
+	PromptProfileAnalysis = "profile_analysis"
+	PromptTaskAnalysis    = "task_analysis"
+	PromptProfileChat     = "profile_chat"
+)
+
+//go:embed prompts
+var builtinPrompts embed.FS
+
+// Registry хранит именованные версии промптов (text/template) и выбирает
+// версию для сессии: по умолчанию или кандидата эксперимента.
+//
+// Файлы промптов лежат как <имя>/<версия>.tmpl (см. каталог prompts).
+// Пользовательский ввод подставляется через UserInput и экранируется.
+type Registry struct {
+	mutex       sync.RWMutex
+	templates   map[string]map[string]*template.Template
+	defaults    map[string]string
+	experiments map[string]Experiment
+}
+
+// Experiment направляет Percent процентов сессий на версию Candidate,
+// остальные получают версию по умолчанию. Сессия всегда попадает в одну группу.
+type Experiment struct {
+	Name      string
+	Candidate string
+	Percent   int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		templates:   make(map[string]map[string]*template.Template),
+		defaults:    make(map[string]string),
+		experiments: make(map[string]Experiment),
+	}
+}
+
+// DefaultRegistry возвращает реестр со встроенными промптами
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	if err := r.loadFS(builtinPrompts, "prompts"); err != nil {
+		panic(fmt.Sprintf("built-in prompts are invalid: %v", err))
+	}
+	return r
+}
+
+// Register добавляет версию промпта. Первая зарегистрированная версия
+// становится версией по умолчанию.
+func (r *Registry) Register(name, version, text string) error {
+	if name == "" || version == "" {
+		return errors.New("prompt name and version are required")
+	}
+
+	tmpl, err := template.New(name + "/" + version).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("prompt %s/%s: %w", name, version, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.templates[name] == nil {
+		r.templates[name] = make(map[string]*template.Template)
+	}
+	r.templates[name][version] = tmpl
+	if r.defaults[name] == "" {
+		r.defaults[name] = version
+	}
+	return nil
+}
+
+// LoadDir добавляет промпты из каталога с раскладкой <имя>/<версия>.tmpl.
+// Версии по умолчанию не меняются — новые версии включаются экспериментом.
+func (r *Registry) LoadDir(dir string) error {
+	return r.loadFS(os.DirFS(dir), ".")
+}
+
+func (r *Registry) loadFS(fsys fs.FS, root string) error {
+	files, err := fs.Glob(fsys, path.Join(root, "*", "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	// Старшие версии регистрируются последними, чтобы по умолчанию была младшая
+	sort.Slice(files, func(a, b int) bool { return versionLess(files[a], files[b]) })
+
+	var errs []error
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := path.Base(path.Dir(file))
+		version := strings.TrimSuffix(path.Base(file), ".tmpl")
+		if err := r.Register(name, version, string(data)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetDefault делает version версией промпта по умолчанию
+func (r *Registry) SetDefault(name, version string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.templates[name][version]; !ok {
+		return fmt.Errorf("unknown prompt %s/%s", name, version)
+	}
+	r.defaults[name] = version
+	return nil
+}
+
+// SetExperiment запускает эксперимент; Percent == 0 его останавливает
+func (r *Registry) SetExperiment(e Experiment) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e.Percent < 0 || e.Percent > 100 {
+		return fmt.Errorf("experiment %s: percent must be 0..100, got %d", e.Name, e.Percent)
+	}
+	if _, ok := r.templates[e.Name][e.Candidate]; !ok {
+		return fmt.Errorf("experiment %s: unknown prompt %s/%s", e.Name, e.Name, e.Candidate)
+	}
+	if e.Percent == 0 {
+		delete(r.experiments, e.Name)
+		return nil
+	}
+	r.experiments[e.Name] = e
+	return nil
+}
+
+// Select выбирает версию промпта name для сессии key
+func (r *Registry) Select(name, key string) (string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	version, ok := r.defaults[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt %s", name)
+	}
+	if e, ok := r.experiments[name]; ok && bucket(name, key) < e.Percent {
+		version = e.Candidate
+	}
+	return version, nil
+}
+
+// Render подставляет data в версию промпта
+func (r *Registry) Render(name, version string, data interface{}) (string, error) {
+	r.mutex.RLock()
+	tmpl, ok := r.templates[name][version]
+	r.mutex.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown prompt %s/%s", name, version)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ParseExperiments разбирает описание экспериментов вида
+// "profile:v3:20,task:v3:50" (имя:версия-кандидат:процент сессий)
+func ParseExperiments(spec string) ([]Experiment, error) {
+	var experiments []Experiment
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid experiment %q, want name:version:percent", item)
+		}
+		percent, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid experiment %q: %w", item, err)
+		}
+		experiments = append(experiments, Experiment{Name: parts[0], Candidate: parts[1], Percent: percent})
+	}
+	return experiments, nil
+}
+
+// bucket детерминированно относит сессию к одному из 100 сегментов
+func bucket(name, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + "|" + key))
+	return int(h.Sum32() % 100)
+}
+
+// versionLess сравнивает пути вида profile/v10.tmpl с учетом номера версии
+func versionLess(a, b string) bool {
+	na, nb := versionNumber(a), versionNumber(b)
+	if na != nb {
+		return na < nb
+	}
+	return a < b
+}
+
+func versionNumber(file string) int {
+	version := strings.TrimSuffix(path.Base(file), ".tmpl")
+	n, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,151 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writePrompt(t *testing.T, dir, name, version, text string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, version+".tmpl"), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDirOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, PromptTaskAnalysis, "v2", "Свой промпт: <answer>{{.Answer}}</answer>")
+	writePrompt(t, dir, PromptTaskAnalysis, "v10", "Кандидат: {{.Answer}}")
+
+	r := DefaultRegistry()
+	if err := r.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+
+	// Версия по умолчанию прежняя, но ее текст взят из каталога
+	version, err := r.Select(PromptTaskAnalysis, "session")
+	if err != nil || version != "v2" {
+		t.Fatalf("Select = %q, %v; want the default v2", version, err)
+	}
+	prompt, err := r.Render(PromptTaskAnalysis, version, promptData{Answer: "<b>"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if prompt != "Свой промпт: <answer>&lt;b&gt;</answer>" {
+		t.Errorf("Render = %q, want the template from the directory", prompt)
+	}
+
+	if _, err := r.Render(PromptTaskAnalysis, "v10", promptData{Answer: "x"}); err != nil {
+		t.Errorf("new version from the directory is not registered: %v", err)
+	}
+}
+
+func TestLoadDirRejectsBrokenTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, PromptTask, "v3", "{{.Answers")
+
+	if err := DefaultRegistry().LoadDir(dir); err == nil {
+		t.Error("LoadDir accepted a template that does not parse")
+	}
+}
+
+func TestBuiltinDefaultIsLowestVersion(t *testing.T) {
+	r := NewRegistry()
+	dir := t.TempDir()
+	for _, version := range []string{"v10", "v2", "v9"} {
+		writePrompt(t, dir, "demo", version, version)
+	}
+	if err := r.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	if version, _ := r.Select("demo", "session"); version != "v2" {
+		t.Errorf("default version = %q, want v2 (v10 sorts after v9)", version)
+	}
+}
+
+func TestExperimentBuckets(t *testing.T) {
+	r := DefaultRegistry()
+	dir := t.TempDir()
+	writePrompt(t, dir, PromptTask, "v3", "кандидат")
+	if err := r.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	if err := r.SetExperiment(Experiment{Name: PromptTask, Candidate: "v3", Percent: 30}); err != nil {
+		t.Fatalf("SetExperiment: %v", err)
+	}
+
+	candidates := 0
+	for n := 0; n < 1000; n++ {
+		key := fmt.Sprintf("%d:%d", n, n*7919)
+		first, err := r.Select(PromptTask, key)
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		// Сессия всегда получает ту же версию
+		for k := 0; k < 3; k++ {
+			if again, _ := r.Select(PromptTask, key); again != first {
+				t.Fatalf("session %s got %s, then %s", key, first, again)
+			}
+		}
+		if first == "v3" {
+			candidates++
+		}
+	}
+	if candidates < 200 || candidates > 400 {
+		t.Errorf("%d of 1000 sessions got the candidate, want about 30%%", candidates)
+	}
+
+	if got := bucket(PromptTask, "42:1"); got != bucket(PromptTask, "42:1") || got < 0 || got >= 100 {
+		t.Errorf("bucket = %d, want a stable value in 0..99", got)
+	}
+
+	if err := r.SetExperiment(Experiment{Name: PromptTask, Candidate: "v3", Percent: 0}); err != nil {
+		t.Fatalf("stop experiment: %v", err)
+	}
+	for n := 0; n < 100; n++ {
+		if version, _ := r.Select(PromptTask, fmt.Sprint(n)); version != "v2" {
+			t.Fatalf("stopped experiment still selects %s", version)
+		}
+	}
+}
+
+func TestSetExperimentValidates(t *testing.T) {
+	r := DefaultRegistry()
+	for _, e := range []Experiment{
+		{Name: PromptTask, Candidate: "v99", Percent: 10},
+		{Name: "unknown", Candidate: "v2", Percent: 10},
+		{Name: PromptTask, Candidate: "v2", Percent: 101},
+		{Name: PromptTask, Candidate: "v2", Percent: -1},
+	} {
+		if err := r.SetExperiment(e); err == nil {
+			t.Errorf("SetExperiment(%+v) accepted", e)
+		}
+	}
+}
+
+func TestParseExperiments(t *testing.T) {
+	got, err := ParseExperiments(" profile:v3:20, task:v4:50 ,")
+	if err != nil {
+		t.Fatalf("ParseExperiments: %v", err)
+	}
+	want := []Experiment{{Name: "profile", Candidate: "v3", Percent: 20}, {Name: "task", Candidate: "v4", Percent: 50}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseExperiments = %+v, want %+v", got, want)
+	}
+
+	if got, err := ParseExperiments(""); err != nil || len(got) != 0 {
+		t.Errorf("ParseExperiments(\"\") = %+v, %v; want none", got, err)
+	}
+	for _, spec := range []string{"profile:v3", "profile:v3:many", "profile:v3:20:1"} {
+		if _, err := ParseExperiments(spec); err == nil || !strings.Contains(err.Error(), "invalid experiment") {
+			t.Errorf("ParseExperiments(%q) = %v, want an error", spec, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package extractor
+
+import "viget-mvp/pkg/gpt"
+
+// Схемы ответов модели. Ответ, не прошедший проверку, отправляется модели
+// на исправление (см. gpt.CompleteJSON).
+
+var (
+	stringList = &gpt.Schema{Type: "array", Items: &gpt.Schema{Type: "string"}}
+	skillLevel = &gpt.Schema{Type: "number", Minimum: gpt.Bound(1), Maximum: gpt.Bound(5)}
+)
+
+var profileAnalysisSchema = &gpt.Schema{
+	Type: "object",
+	Properties: map[string]*gpt.Schema{
+		"mentioned_skills": stringList,
+		"experience_level": {Type: "string", Enum: []string{"none", "junior", "middle", "senior"}},
+		"interests":        stringList,
+		"key_info":         {Type: "string"},
+	},
+}
+
+var taskAnalysisSchema = &gpt.Schema{
+	Type: "object",
+	Properties: map[string]*gpt.Schema{
+		"mentioned_technologies": stringList,
+		"task_complexity":        {Type: "string", Enum: []string{"simple", "medium", "complex"}},
+		"project_type":           {Type: "string", Enum: []string{"web", "mobile", "data", "design", "other"}},
+		"key_info":               {Type: "string"},
+	},
+}
+
+var profileSchema = &gpt.Schema{
+	Type:     "object",
+	Required: []string{"name", "skills"},
+	Properties: map[string]*gpt.Schema{
+		"name": {Type: "string"},
+		"skills": {
+			Type: "object",
+			AdditionalProperties: &gpt.Schema{
+				Type:     "object",
+				Required: []string{"level"},
+				Properties: map[string]*gpt.Schema{
+					"level":      skillLevel,
+					"confidence": {Type: "number", Minimum: gpt.Bound(0), Maximum: gpt.Bound(1)},
+				},
+			},
+		},
+		"soft_skills":    stringList,
+		"interests":      stringList,
+		"goals":          stringList,
+		"hours_per_week": {Type: "number", Minimum: gpt.Bound(0), Maximum: gpt.Bound(168)},
+		"min_budget":     {Type: "number", Minimum: gpt.Bound(0)},
+		"experience": {
+			Type: "array",
+			Items: &gpt.Schema{
+				Type: "object",
+				Properties: map[string]*gpt.Schema{
+					"company":     {Type: "string"},
+					"position":    {Type: "string"},
+					"duration":    {Type: "string"},
+					"description": {Type: "string"},
+					"skills":      stringList,
+				},
+			},
+		},
+	},
+}
+
+var taskSchema = &gpt.Schema{
+	Type:     "object",
+	Required: []string{"title", "description", "required_skills"},
+	Properties: map[string]*gpt.Schema{
+		"title":           {Type: "string"},
+		"description":     {Type: "string"},
+		"required_skills": {Type: "object", AdditionalProperties: skillLevel},
+		"budget":          {Type: "number", Minimum: gpt.Bound(0)},
+		"deadline_days":   {Type: "number", Minimum: gpt.Bound(0)},
+	},
+}
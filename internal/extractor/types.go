@@ -0,0 +1,144 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+// ProfileData — профиль, извлеченный моделью из ответов интервью
+type ProfileData struct {
+	Name       string               `json:"name"`
+	Skills     map[string]SkillData `json:"skills"`
+	SoftSkills []string             `json:"soft_skills"`
+	Interests  []string             `json:"interests"`
+	Goals      []string             `json:"goals"`
+	Experience []ExperienceData     `json:"experience"`
+	// HoursPerWeek — сколько часов в неделю пользователь готов уделять задачам
+	HoursPerWeek float64 `json:"hours_per_week"`
+	// MinBudget — с какого бюджета задача интересна пользователю, ₽
+	MinBudget float64 `json:"min_budget"`
+
+	// PromptVersion — промпт, которым получены данные, например "profile/v2"
+	PromptVersion string `json:"-"`
+}
+
+// SkillData — уровень навыка 1-5 и уверенность модели в оценке 0-1.
+// Числа модель может вернуть дробными, поэтому они float64.
+type SkillData struct {
+	Level      float64 `json:"level"`
+	Confidence float64 `json:"confidence"`
+}
+
+type ExperienceData struct {
+	Company     string   `json:"company"`
+	Position    string   `json:"position"`
+	Duration    string   `json:"duration"`
+	Description string   `json:"description"`
+	Skills      []string `json:"skills"`
+}
+
+// TaskData — задача, извлеченная моделью из ответов интервью
+type TaskData struct {
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	RequiredSkills map[string]float64 `json:"required_skills"` // навык -> минимальный уровень
+	Budget         float64            `json:"budget"`
+	DeadlineDays   float64            `json:"deadline_days"`
+
+	PromptVersion string `json:"-"`
+}
+
+// fullTimeHours — часов в неделю при полной занятости: столько свободного
+// времени дает доступность 1
+const fullTimeHours = 40
+
+// ToUserProfile переносит извлеченные данные в профиль пользователя
+func (p *ProfileData) ToUserProfile(userID int64, now time.Time) *models.UserProfile {
+	profile := &models.UserProfile{
+		ID:         strconv.FormatInt(userID, 10),
+		TelegramID: userID,
+		Name:       strings.TrimSpace(p.Name),
+		Skills:     make(map[string]models.SkillLevel),
+		SoftSkills: cleanList(p.SoftSkills),
+		Interests:  cleanList(p.Interests),
+		Goals:      cleanList(p.Goals),
+		Verified:   make(map[string]bool),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+
+		Availability: math.Max(0, math.Min(1, p.HoursPerWeek/fullTimeHours)),
+		MinBudget:    int(math.Round(math.Max(0, p.MinBudget))),
+
+		PromptVersion: p.PromptVersion,
+	}
+
+	for name, skill := range p.Skills {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		profile.Skills[name] = models.SkillLevel{
+			Name:       name,
+			Level:      clampLevel(skill.Level),
+			Confidence: math.Max(0, math.Min(1, skill.Confidence)),
+			Source:     "interview",
+		}
+	}
+
+	for _, exp := range p.Experience {
+		profile.Experience = append(profile.Experience, models.Experience{
+			Company:     exp.Company,
+			Position:    exp.Position,
+			Duration:    exp.Duration,
+			Description: exp.Description,
+			Skills:      cleanList(exp.Skills),
+		})
+	}
+
+	return profile
+}
+
+// ToTaskProfile переносит извлеченные данные в задачу; срок отсчитывается от createdAt
+func (t *TaskData) ToTaskProfile(userID int64, createdAt time.Time) *models.TaskProfile {
+	task := &models.TaskProfile{
+		ID:             fmt.Sprintf("task_%d", userID),
+		Title:          strings.TrimSpace(t.Title),
+		Description:    strings.TrimSpace(t.Description),
+		RequiredSkills: make(map[string]int),
+		Budget:         int(math.Round(t.Budget)),
+		CreatedBy:      models.TaskAuthor(userID),
+		Status:         "open",
+		CreatedAt:      createdAt,
+		PromptVersion:  t.PromptVersion,
+	}
+
+	for name, level := range t.RequiredSkills {
+		if name = strings.TrimSpace(name); name != "" {
+			task.RequiredSkills[name] = clampLevel(level)
+		}
+	}
+	if t.DeadlineDays > 0 {
+		task.Deadline = createdAt.AddDate(0, 0, int(math.Ceil(t.DeadlineDays)))
+	}
+
+	return task
+}
+
+func clampLevel(level float64) int {
+	return int(math.Max(1, math.Min(5, math.Round(level))))
+}
+
+func cleanList(items []string) []string {
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -1,18 +1,61 @@
 package matcher
 
 import (
-	"fmt"
 	"math"
 	"sort"
-	"strings"
+	"sync/atomic"
+	"time"
 
 	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+	"viget-mvp/internal/skills"
 )
 
-type Matcher struct{}
+// Matcher оценивает совпадение пользователя и задачи взвешенной суммой факторов
+// (навыки, интересы, доступность, бюджет, сроки, подтверждения).
+// Веса и порог задаются Config, так что ранжирование настраивается без кода.
+type Matcher struct {
+	factors   []weightedFactor
+	threshold float64
+	// taxonomy сравнивает навыки по каноническим именам (nil — как записаны)
+	taxonomy atomic.Pointer[skills.Taxonomy]
+}
+
+type weightedFactor struct {
+	factor Factor
+	weight float64
+}
+
+// NewMatcher создает подбор с весами и порогом из cfg
+func NewMatcher(cfg Config) (*Matcher, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 
-func NewMatcher() *Matcher {
-	return &Matcher{}
+	builtin := map[string]Factor{
+		FactorSkills:       skillsFactor{graph: cfg.SkillGraph},
+		FactorInterests:    interestsFactor{similarity: cfg.InterestSimilarity},
+		FactorAvailability: availabilityFactor{},
+		FactorBudget:       budgetFactor{},
+		FactorDeadline:     deadlineFactor{comfortDays: cfg.DeadlineDays, now: time.Now},
+		FactorVerification: verificationFactor{},
+		FactorReputation:   reputationFactor{},
+	}
+
+	m := &Matcher{threshold: cfg.Threshold}
+	for _, name := range factorOrder {
+		if w := cfg.Weights[name]; w > 0 {
+			m.factors = append(m.factors, weightedFactor{factor: builtin[name], weight: w})
+		}
+	}
+	return m, nil
+}
+
+// SetTaxonomy включает сравнение навыков по каноническим именам: "JS" в
+// профиле засчитывается задаче, которой нужен "JavaScript". Подходит и для
+// профилей, сохраненных до появления синонимов.
+func (m *Matcher) SetTaxonomy(taxonomy *skills.Taxonomy) {
+	m.taxonomy.Store(taxonomy)
 }
 
 func (m *Matcher) FindMatchingTasks(user *models.UserProfile, tasks []*models.TaskProfile) []models.MatchResult {
@@ -23,10 +66,8 @@ func (m *Matcher) FindMatchingTasks(user *models.UserProfile, tasks []*models.Ta
 			continue
 		}
 
-		score := m.calculateMatchScore(user, task)
-		reasons := m.generateMatchReasons(user, task, score)
-
-		if score > 0.3 { // Минимальный порог совпадения
+		score, reasons := m.Score(user, task)
+		if score > m.threshold { // Минимальный порог совпадения
 			matches = append(matches, models.MatchResult{
 				TaskID:  task.ID,
 				UserID:  user.ID,
@@ -44,112 +85,134 @@ func (m *Matcher) FindMatchingTasks(user *models.UserProfile, tasks []*models.Ta
 	return matches
 }
 
-func (m *Matcher) calculateMatchScore(user *models.UserProfile, task *models.TaskProfile) float64 {
-	skillScore := m.calculateSkillMatch(user, task)
-	interestScore := m.calculateInterestMatch(user, task)
-
-	// Веса: навыки 80%, интересы 20%
-	totalScore := skillScore*0.8 + interestScore*0.2
-
-	return math.Min(1.0, totalScore)
-}
-
-func (m *Matcher) calculateSkillMatch(user *models.UserProfile, task *models.TaskProfile) float64 {
-	if len(task.RequiredSkills) == 0 {
-		return 0.5 // Нейтральная оценка, если требования не указаны
-	}
+// Score возвращает оценку совпадения от 0 до 1 и ее причины. Факторы без данных
+// (например, пользователь не указал доступность) в оценке не участвуют.
+func (m *Matcher) Score(user *models.UserProfile, task *models.TaskProfile) (float64, []string) {
+	user, task = m.canonical(user, task)
 
-	var totalScore float64
-	var matchedSkills int
-
-	for requiredSkill, minLevel := range task.RequiredSkills {
-		userSkill, hasSkill := user.Skills[requiredSkill]
-
-		if hasSkill {
-			if userSkill.Level >= minLevel {
-				// Бонус за превышение минимального уровня
-				bonus := float64(userSkill.Level-minLevel) * 0.1
-				skillScore := 1.0 + bonus
-				totalScore += skillScore
-			} else {
-				// Частичное совпадение, если уровень ниже требуемого
-				ratio := float64(userSkill.Level) / float64(minLevel)
-				totalScore += ratio * 0.7
-			}
-			matchedSkills++
+	var total, weights float64
+	var reasons []string
+	for _, wf := range m.factors {
+		score, factorReasons, ok := wf.factor.Score(user, task)
+		if !ok {
+			continue
 		}
-		// Если навыка нет, добавляем 0 к общему счету
+		total += score * wf.weight
+		weights += wf.weight
+		reasons = append(reasons, factorReasons...)
+	}
+	if weights == 0 {
+		return 0, reasons
 	}
 
-	if matchedSkills == 0 {
-		return 0
+	score := math.Min(1.0, total/weights)
+
+	// Общая оценка
+	if score > 0.8 {
+		reasons = append([]string{"🎯 Отличное совпадение!"}, reasons...)
+	} else if score > 0.6 {
+		reasons = append([]string{"👍 Хорошее совпадение"}, reasons...)
+	} else if score > 0.4 {
+		reasons = append([]string{"🤔 Частичное совпадение"}, reasons...)
 	}
 
-	averageScore := totalScore / float64(len(task.RequiredSkills))
+	return score, reasons
+}
 
-	// Штраф за отсутствующие навыки
-	skillCoverage := float64(matchedSkills) / float64(len(task.RequiredSkills))
+// canonical возвращает копии профиля и задачи с каноническими названиями навыков
+func (m *Matcher) canonical(user *models.UserProfile, task *models.TaskProfile) (*models.UserProfile, *models.TaskProfile) {
+	taxonomy := m.taxonomy.Load()
+	if taxonomy == nil {
+		return user, task
+	}
 
-	return averageScore * skillCoverage
+	u, t := *user, *task
+	u.Skills = taxonomy.CanonicalSkills(user.Skills)
+	u.Verified = taxonomy.CanonicalSet(user.Verified)
+	t.RequiredSkills = taxonomy.CanonicalLevels(task.RequiredSkills)
+	return &u, &t
 }
 
-func (m *Matcher) calculateInterestMatch(user *models.UserProfile, task *models.TaskProfile) float64 {
-	if len(user.Interests) == 0 {
-		return 0.5
+func (m *Matcher) RecommendTopTasks(user *models.UserProfile, tasks []*models.TaskProfile, topN int) []models.MatchResult {
+	matches := m.FindMatchingTasks(user, tasks)
+	if len(matches) > topN {
+		return matches[:topN]
 	}
+	return matches
+}
 
-	taskText := strings.ToLower(task.Title + " " + task.Description)
-	var matchCount int
+// RecommendForUser подбирает задачи для пользователя из хранилища
+// и сохраняет найденные совпадения.
+func (m *Matcher) RecommendForUser(store profile.Store, userID string, topN int) ([]models.MatchResult, error) {
+	user, err := store.GetUserProfileByID(userID)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, interest := range user.Interests {
-		if strings.Contains(taskText, strings.ToLower(interest)) {
-			matchCount++
+	matches := m.RecommendTopTasks(user, store.GetAvailableTasks(), topN)
+	for i := range matches {
+		if err := store.SaveMatch(&matches[i]); err != nil {
+			return nil, err
 		}
 	}
 
-	return float64(matchCount) / float64(len(user.Interests))
+	return matches, nil
 }
 
-func (m *Matcher) generateMatchReasons(user *models.UserProfile, task *models.TaskProfile, score float64) []string {
-	var reasons []string
+// FindCandidates оценивает исполнителей для задачи и возвращает тех, кто
+// прошел порог, по убыванию оценки. Автор задачи в кандидаты не попадает.
+func (m *Matcher) FindCandidates(task *models.TaskProfile, users []*models.UserProfile) []models.MatchResult {
+	var matches []models.MatchResult
 
-	// Анализируем совпадения навыков
-	for requiredSkill, minLevel := range task.RequiredSkills {
-		if userSkill, hasSkill := user.Skills[requiredSkill]; hasSkill {
-			if userSkill.Level >= minLevel {
-				reasons = append(reasons, fmt.Sprintf("✅ %s: ваш уровень %d/%d", requiredSkill, userSkill.Level, minLevel))
-			} else {
-				reasons = append(reasons, fmt.Sprintf("⚠️ %s: ваш уровень %d/%d (ниже требуемого)", requiredSkill, userSkill.Level, minLevel))
-			}
-		} else {
-			reasons = append(reasons, fmt.Sprintf("❌ %s: навык отсутствует", requiredSkill))
+	for _, user := range users {
+		if task.CreatedBy == models.TaskAuthor(user.TelegramID) {
+			continue
 		}
-	}
 
-	// Анализируем совпадения интересов
-	taskText := strings.ToLower(task.Title + " " + task.Description)
-	for _, interest := range user.Interests {
-		if strings.Contains(taskText, strings.ToLower(interest)) {
-			reasons = append(reasons, fmt.Sprintf("💡 Совпадает с вашим интересом: %s", interest))
+		score, reasons := m.Score(user, task)
+		if score > m.threshold {
+			matches = append(matches, models.MatchResult{
+				TaskID:  task.ID,
+				UserID:  user.ID,
+				Score:   score,
+				Reasons: reasons,
+			})
 		}
 	}
 
-	// Общая оценка
-	if score > 0.8 {
-		reasons = append([]string{"🎯 Отличное совпадение!"}, reasons...)
-	} else if score > 0.6 {
-		reasons = append([]string{"👍 Хорошее совпадение"}, reasons...)
-	} else if score > 0.4 {
-		reasons = append([]string{"🤔 Частичное совпадение"}, reasons...)
-	}
+	// При равной оценке порядок не должен меняться от запроса к запросу
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].UserID < matches[j].UserID
+	})
 
-	return reasons
+	return matches
 }
 
-func (m *Matcher) RecommendTopTasks(user *models.UserProfile, tasks []*models.TaskProfile, topN int) []models.MatchResult {
-	matches := m.FindMatchingTasks(user, tasks)
+func (m *Matcher) RecommendTopCandidates(task *models.TaskProfile, users []*models.UserProfile, topN int) []models.MatchResult {
+	matches := m.FindCandidates(task, users)
 	if len(matches) > topN {
 		return matches[:topN]
 	}
 	return matches
 }
+
+// RecommendForTask подбирает исполнителей для задачи из хранилища
+// и сохраняет найденные совпадения.
+func (m *Matcher) RecommendForTask(store profile.Store, taskID string, topN int) ([]models.MatchResult, error) {
+	task, err := store.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := m.RecommendTopCandidates(task, store.ListUserProfiles(), topN)
+	for i := range matches {
+		if err := store.SaveMatch(&matches[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,102 @@
+package matcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+	"viget-mvp/internal/semantic"
+	"viget-mvp/internal/skills"
+)
+
+func TestSetTaxonomyWhileScoring(t *testing.T) {
+	m, err := NewMatcher(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	user := &models.UserProfile{ID: "1", Skills: map[string]models.SkillLevel{"JS": {Name: "JS", Level: 4}}}
+	task := &models.TaskProfile{ID: "t", Status: "open", RequiredSkills: map[string]int{"JavaScript": 3}}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 4; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 50; k++ {
+				m.Score(user, task)
+			}
+		}()
+	}
+	taxonomy := skills.NewTaxonomy(skills.Builtin)
+	for k := 0; k < 50; k++ {
+		m.SetTaxonomy(taxonomy)
+	}
+	wg.Wait()
+
+	// С таксономией "JS" засчитывается задаче, которой нужен "JavaScript"
+	if score, _ := m.Score(user, task); score == 0 {
+		t.Error("JS did not count for JavaScript after SetTaxonomy")
+	}
+}
+
+func TestRefreshWhileMatching(t *testing.T) {
+	store := profile.NewInMemoryStorage()
+	if err := profile.SeedDemoTasks(store); err != nil {
+		t.Fatalf("SeedDemoTasks: %v", err)
+	}
+	reader := &models.UserProfile{ID: "1", TelegramID: 1, Interests: []string{"веб-разработка"}}
+	if err := store.SaveUserProfile(reader); err != nil {
+		t.Fatalf("SaveUserProfile: %v", err)
+	}
+	updatedAt := reader.UpdatedAt
+
+	m, err := NewMatcher(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	index := semantic.NewIndex(semantic.NewLocalEmbedder(nil), semantic.LocalModel)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 20; k++ {
+			if _, err := index.Refresh(context.Background(), store); err != nil {
+				t.Errorf("Refresh: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 20; k++ {
+			user := &models.UserProfile{ID: "2", TelegramID: 2, Interests: []string{fmt.Sprintf("мобильные приложения %d", k)}}
+			if err := store.SaveUserProfile(user); err != nil {
+				t.Errorf("SaveUserProfile: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 20; k++ {
+			if _, err := m.RecommendForUser(store, "1", 5); err != nil {
+				t.Errorf("RecommendForUser: %v", err)
+				return
+			}
+			m.FindCandidates(store.ListTasks()[0], store.ListUserProfiles())
+		}
+	}()
+	wg.Wait()
+
+	if _, err := index.Refresh(context.Background(), store); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	saved := store.GetUserProfile("1")
+	if saved.Embedding == nil || !saved.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("after Refresh: embedding %v, UpdatedAt %v (was %v)", saved.Embedding != nil, saved.UpdatedAt, updatedAt)
+	}
+}
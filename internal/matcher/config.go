@@ -0,0 +1,118 @@
+package matcher
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"viget-mvp/internal/skills"
+)
+
+// Config задает веса факторов и порог совпадения. Веса не обязаны давать
+// в сумме единицу: оценка нормируется на сумму весов факторов, по которым
+// есть данные. Фактор с весом 0 не учитывается.
+type Config struct {
+	Weights   map[string]float64
+	Threshold float64
+	// DeadlineDays — за сколько дней до срока задача считается комфортной
+	DeadlineDays int
+	// SkillGraph — связи навыков: близкий навык засчитывается частично
+	// (nil — только точные совпадения)
+	SkillGraph *skills.Graph
+	// InterestSimilarity — с какой близости векторов (косинус) интерес считается
+	// совпавшим с задачей; ниже засчитывается пропорционально
+	InterestSimilarity float64
+}
+
+// DefaultConfig — навыки 80%, интересы 20%, порог 0.3, встроенные связи навыков
+func DefaultConfig() Config {
+	return Config{
+		Weights: map[string]float64{
+			FactorSkills:    0.8,
+			FactorInterests: 0.2,
+		},
+		Threshold:    0.3,
+		DeadlineDays: 7,
+		SkillGraph:   skills.NewGraph(skills.BuiltinEdges),
+
+		InterestSimilarity: 0.25,
+	}
+}
+
+// ParseWeights разбирает веса вида "skills=0.6,interests=0.2,availability=0.2"
+func ParseWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("weight %q: want name=value", part)
+		}
+		name = strings.TrimSpace(name)
+		if !knownFactor(name) {
+			return nil, fmt.Errorf("weight %q: unknown factor %q (known: %s)", part, name, strings.Join(factorNames(), ", "))
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight %q: want a non-negative number", part)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// WithWeights возвращает копию конфигурации, в которой weights заменяют веса тех же факторов
+func (c Config) WithWeights(weights map[string]float64) Config {
+	merged := make(map[string]float64, len(c.Weights)+len(weights))
+	for name, w := range c.Weights {
+		merged[name] = w
+	}
+	for name, w := range weights {
+		merged[name] = w
+	}
+	c.Weights = merged
+	return c
+}
+
+func (c Config) validate() error {
+	var total float64
+	for name, w := range c.Weights {
+		if !knownFactor(name) {
+			return fmt.Errorf("unknown factor %q", name)
+		}
+		if w < 0 {
+			return fmt.Errorf("factor %s: negative weight %g", name, w)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("all factor weights are zero")
+	}
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("threshold must be 0..1, got %g", c.Threshold)
+	}
+	if c.InterestSimilarity <= 0 || c.InterestSimilarity > 1 {
+		return fmt.Errorf("interest similarity must be in (0, 1], got %g", c.InterestSimilarity)
+	}
+	return nil
+}
+
+func factorNames() []string {
+	names := make([]string, 0, len(factorOrder))
+	names = append(names, factorOrder...)
+	sort.Strings(names)
+	return names
+}
+
+func knownFactor(name string) bool {
+	for _, known := range factorOrder {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package matcher
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"viget-mvp/internal/models"
+)
+
+func newTestMatcher(t *testing.T, weights map[string]float64, threshold float64) *Matcher {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.Weights = weights
+	cfg.Threshold = threshold
+	m, err := NewMatcher(cfg)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+	return m
+}
+
+func TestParseWeights(t *testing.T) {
+	got, err := ParseWeights(" skills=0.6, budget=0.3 ,reputation=0.1,")
+	if err != nil {
+		t.Fatalf("ParseWeights: %v", err)
+	}
+	want := map[string]float64{FactorSkills: 0.6, FactorBudget: 0.3, FactorReputation: 0.1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWeights = %v, want %v", got, want)
+	}
+
+	for _, s := range []string{"skills", "talent=1", "skills=-0.5", "skills=много"} {
+		if _, err := ParseWeights(s); err == nil {
+			t.Errorf("ParseWeights(%q) accepted", s)
+		}
+	}
+}
+
+func TestNewMatcherRejectsInvalidConfig(t *testing.T) {
+	tests := map[string]func(*Config){
+		"unknown factor":  func(c *Config) { c.Weights = map[string]float64{"talent": 1} },
+		"negative weight": func(c *Config) { c.Weights = map[string]float64{FactorSkills: -1} },
+		"all zero":        func(c *Config) { *c = c.WithWeights(map[string]float64{FactorSkills: 0, FactorInterests: 0}) },
+		"threshold":       func(c *Config) { c.Threshold = 1.5 },
+		"similarity":      func(c *Config) { c.InterestSimilarity = 0 },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			change(&cfg)
+			if _, err := NewMatcher(cfg); err == nil {
+				t.Error("NewMatcher accepted the config")
+			}
+		})
+	}
+}
+
+func TestWithWeightsKeepsOtherFactors(t *testing.T) {
+	cfg := DefaultConfig()
+	merged := cfg.WithWeights(map[string]float64{FactorBudget: 0.5})
+
+	want := map[string]float64{FactorSkills: 0.8, FactorInterests: 0.2, FactorBudget: 0.5}
+	if !reflect.DeepEqual(merged.Weights, want) {
+		t.Errorf("WithWeights = %v, want %v", merged.Weights, want)
+	}
+	if _, ok := cfg.Weights[FactorBudget]; ok {
+		t.Error("WithWeights changed the original config")
+	}
+}
+
+func TestScoreRenormalizesFactorsWithoutData(t *testing.T) {
+	m := newTestMatcher(t, map[string]float64{
+		FactorSkills:       0.5,
+		FactorBudget:       0.5,
+		FactorAvailability: 1,
+		FactorReputation:   1,
+	}, 0.3)
+	user := &models.UserProfile{Skills: map[string]models.SkillLevel{"Go": {Name: "Go", Level: 3}}}
+	task := &models.TaskProfile{RequiredSkills: map[string]int{"Go": 3}, Budget: 50000}
+
+	// Ни бюджета, ни доступности, ни репутации: оценка — только навыки
+	if score, _ := m.Score(user, task); score != 1 {
+		t.Errorf("score without data = %g, want 1", score)
+	}
+
+	user.MinBudget = 100000
+	if score, _ := m.Score(user, task); math.Abs(score-0.75) > 1e-9 {
+		t.Errorf("score with half the budget = %g, want (1*0.5 + 0.5*0.5) / 1 = 0.75", score)
+	}
+}
+
+func TestFindMatchingTasksThreshold(t *testing.T) {
+	m := newTestMatcher(t, map[string]float64{FactorSkills: 1}, 0.5)
+	user := &models.UserProfile{ID: "u", Skills: map[string]models.SkillLevel{"Go": {Name: "Go", Level: 3}}}
+	tasks := []*models.TaskProfile{
+		{ID: "match", Status: "open", RequiredSkills: map[string]int{"Go": 3}},
+		{ID: "closed", Status: "closed", RequiredSkills: map[string]int{"Go": 3}},
+		{ID: "weak", Status: "open", RequiredSkills: map[string]int{"Go": 3, "Rust": 3}},
+		// Без требований оценка нейтральная, 0.5 — ровно порог и не проходит
+		{ID: "neutral", Status: "open"},
+		// Уровень 3 из 4: 3/4*0.7 = 0.525 — проходит, но ниже полного совпадения
+		{ID: "partial", Status: "open", RequiredSkills: map[string]int{"Go": 4}},
+	}
+
+	var got []string
+	for _, match := range m.FindMatchingTasks(user, tasks) {
+		if match.Score <= 0.5 {
+			t.Errorf("task %s passed with score %g", match.TaskID, match.Score)
+		}
+		got = append(got, match.TaskID)
+	}
+	if want := []string{"match", "partial"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindMatchingTasks = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,259 @@
+package matcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/semantic"
+	"viget-mvp/internal/skills"
+)
+
+// Имена факторов в конфигурации весов
+const (
+	FactorSkills       = "skills"
+	FactorInterests    = "interests"
+	FactorAvailability = "availability"
+	FactorBudget       = "budget"
+	FactorDeadline     = "deadline"
+	FactorVerification = "verification"
+	FactorReputation   = "reputation"
+)
+
+// factorOrder — порядок факторов, в нем же идут причины совпадения
+var factorOrder = []string{
+	FactorSkills,
+	FactorInterests,
+	FactorAvailability,
+	FactorBudget,
+	FactorDeadline,
+	FactorVerification,
+	FactorReputation,
+}
+
+// Factor оценивает одну сторону совпадения пользователя и задачи.
+// score — от 0 до 1 (навыки с запасом могут дать чуть больше), reasons —
+// пояснения; их видят и исполнитель, и автор задачи, поэтому они написаны
+// без обращения к читателю. ok == false — данных для оценки нет, и фактор
+// не участвует в итоговой оценке.
+type Factor interface {
+	Name() string
+	Score(user *models.UserProfile, task *models.TaskProfile) (score float64, reasons []string, ok bool)
+}
+
+// requiredSkills возвращает навыки задачи по алфавиту, чтобы причины шли в одном порядке
+func requiredSkills(task *models.TaskProfile) []string {
+	skills := make([]string, 0, len(task.RequiredSkills))
+	for skill := range task.RequiredSkills {
+		skills = append(skills, skill)
+	}
+	sort.Strings(skills)
+	return skills
+}
+
+// relatedCredit — доля, с которой засчитывается уровень близкого навыка,
+// как и уровень ниже требуемого
+const relatedCredit = 0.7
+
+// skillsFactor — насколько навыки пользователя покрывают требования задачи.
+// Если точного навыка нет, частично засчитывается близкий из graph.
+type skillsFactor struct {
+	graph *skills.Graph
+}
+
+func (skillsFactor) Name() string { return FactorSkills }
+
+func (f skillsFactor) Score(user *models.UserProfile, task *models.TaskProfile) (float64, []string, bool) {
+	if len(task.RequiredSkills) == 0 {
+		return 0.5, nil, true // Нейтральная оценка, если требования не указаны
+	}
+
+	var total float64
+	var matched int
+	var reasons []string
+	for _, skill := range requiredSkills(task) {
+		minLevel := task.RequiredSkills[skill]
+		userSkill, hasSkill := user.Skills[skill]
+		switch {
+		case !hasSkill:
+			related, level, credit := f.related(user, skill, minLevel)
+			if related == "" {
+				reasons = append(reasons, fmt.Sprintf("❌ %s: навык отсутствует", skill))
+				continue
+			}
+			total += credit
+			reasons = append(reasons, fmt.Sprintf("🔗 %s: близкий навык: %s, уровень %d", skill, related, level))
+		case userSkill.Level >= minLevel:
+			// Бонус за превышение минимального уровня
+			total += 1.0 + float64(userSkill.Level-minLevel)*0.1
+			reasons = append(reasons, fmt.Sprintf("✅ %s: уровень %d/%d", skill, userSkill.Level, minLevel))
+		default:
+			// Частичное совпадение, если уровень ниже требуемого
+			total += float64(userSkill.Level) / float64(minLevel) * 0.7
+			reasons = append(reasons, fmt.Sprintf("⚠️ %s: уровень %d/%d (ниже требуемого)", skill, userSkill.Level, minLevel))
+		}
+		matched++
+	}
+
+	if matched == 0 {
+		return 0, reasons, true
+	}
+
+	// Штраф за отсутствующие навыки
+	average := total / float64(len(task.RequiredSkills))
+	coverage := float64(matched) / float64(len(task.RequiredSkills))
+	return average * coverage, reasons, true
+}
+
+// related находит у пользователя навык, лучше всего заменяющий required, и его вклад в оценку
+func (f skillsFactor) related(user *models.UserProfile, required string, minLevel int) (skill string, level int, credit float64) {
+	for _, r := range f.graph.Related(required) {
+		userSkill, ok := user.Skills[r.Skill]
+		if !ok {
+			continue
+		}
+		c := math.Min(1, float64(userSkill.Level)/float64(minLevel)) * relatedCredit * r.Weight
+		if c > credit {
+			skill, level, credit = r.Skill, userSkill.Level, c
+		}
+	}
+	return skill, level, credit
+}
+
+// interestsFactor — насколько интересы пользователя близки по смыслу к задаче.
+// Интерес сравнивается с названием и каждым фрагментом описания, берется
+// лучшее совпадение; интерес с близостью от similarity засчитывается целиком.
+type interestsFactor struct {
+	similarity float64
+}
+
+func (interestsFactor) Name() string { return FactorInterests }
+
+func (f interestsFactor) Score(user *models.UserProfile, task *models.TaskProfile) (float64, []string, bool) {
+	if len(user.Interests) == 0 {
+		return 0.5, nil, true
+	}
+
+	// Векторов еще нет или они получены разными моделями — сравнить нельзя
+	u, t := user.Embedding, task.Embedding
+	if u == nil || t == nil || u.Model != t.Model || len(u.Vectors) != len(user.Interests) || len(t.Vectors) == 0 {
+		return 0, nil, false
+	}
+
+	var total float64
+	var reasons []string
+	for i, interest := range user.Interests {
+		var best float64
+		for _, v := range t.Vectors {
+			best = math.Max(best, semantic.Cosine(u.Vectors[i], v))
+		}
+		total += math.Min(1, best/f.similarity)
+		if best >= f.similarity {
+			reasons = append(reasons, fmt.Sprintf("💡 Совпадает с интересом: %s", interest))
+		}
+	}
+	return total / float64(len(user.Interests)), reasons, true
+}
+
+// availabilityFactor — сколько времени пользователь готов уделять задачам
+type availabilityFactor struct{}
+
+func (availabilityFactor) Name() string { return FactorAvailability }
+
+func (availabilityFactor) Score(user *models.UserProfile, _ *models.TaskProfile) (float64, []string, bool) {
+	if user.Availability <= 0 {
+		return 0, nil, false
+	}
+	score := clamp(user.Availability)
+	if score < 0.3 {
+		return score, []string{"⏳ Мало свободного времени"}, true
+	}
+	return score, nil, true
+}
+
+// budgetFactor — дотягивает ли бюджет задачи до ожиданий пользователя
+type budgetFactor struct{}
+
+func (budgetFactor) Name() string { return FactorBudget }
+
+func (budgetFactor) Score(user *models.UserProfile, task *models.TaskProfile) (float64, []string, bool) {
+	if user.MinBudget <= 0 || task.Budget <= 0 {
+		return 0, nil, false
+	}
+	if task.Budget >= user.MinBudget {
+		return 1, []string{fmt.Sprintf("💰 Бюджет %d ₽ подходит", task.Budget)}, true
+	}
+	return float64(task.Budget) / float64(user.MinBudget),
+		[]string{fmt.Sprintf("💸 Бюджет ниже ожидаемого: %d из %d ₽", task.Budget, user.MinBudget)}, true
+}
+
+// deadlineFactor — хватает ли времени до срока задачи
+type deadlineFactor struct {
+	comfortDays int
+	now         func() time.Time
+}
+
+func (deadlineFactor) Name() string { return FactorDeadline }
+
+func (f deadlineFactor) Score(_ *models.UserProfile, task *models.TaskProfile) (float64, []string, bool) {
+	if task.Deadline.IsZero() || f.comfortDays <= 0 {
+		return 0, nil, false
+	}
+
+	days := task.Deadline.Sub(f.now()).Hours() / 24
+	if days <= 0 {
+		return 0, []string{"⌛ Срок задачи уже прошел"}, true
+	}
+	score := math.Min(1, days/float64(f.comfortDays))
+	if score < 0.5 {
+		return score, []string{fmt.Sprintf("⏰ Сжатые сроки: %.0f дн.", math.Ceil(days))}, true
+	}
+	return score, nil, true
+}
+
+// verificationFactor — доля подтвержденных среди навыков, которые требует задача
+type verificationFactor struct{}
+
+func (verificationFactor) Name() string { return FactorVerification }
+
+func (verificationFactor) Score(user *models.UserProfile, task *models.TaskProfile) (float64, []string, bool) {
+	var matched int
+	var verified []string
+	for _, skill := range requiredSkills(task) {
+		userSkill, ok := user.Skills[skill]
+		if !ok {
+			continue
+		}
+		matched++
+		if userSkill.Verified || user.Verified[skill] {
+			verified = append(verified, skill)
+		}
+	}
+	if matched == 0 {
+		return 0, nil, false
+	}
+
+	var reasons []string
+	if len(verified) > 0 {
+		reasons = append(reasons, "🔒 Подтверждены навыки: "+strings.Join(verified, ", "))
+	}
+	return float64(len(verified)) / float64(matched), reasons, true
+}
+
+// reputationFactor — оценка пользователя по завершенным задачам. Завершение
+// задач в сервисе пока не отслеживается, поэтому данных нет и фактор в оценке
+// не участвует, даже если ему задан вес.
+type reputationFactor struct{}
+
+func (reputationFactor) Name() string { return FactorReputation }
+
+func (reputationFactor) Score(*models.UserProfile, *models.TaskProfile) (float64, []string, bool) {
+	return 0, nil, false
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,80 @@
+package matcher
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/skills"
+)
+
+func TestSkillsFactorCreditsRelatedSkills(t *testing.T) {
+	factor := skillsFactor{graph: skills.NewGraph(skills.BuiltinEdges)}
+	tests := []struct {
+		name   string
+		have   map[string]int
+		need   string
+		want   float64
+		reason string
+	}{
+		// Знающему React JavaScript засчитывается с весом связи 0.8
+		{"child for parent", map[string]int{"React": 4}, "JavaScript", 0.7 * 0.8, "🔗 JavaScript: близкий навык: React, уровень 4"},
+		// Обратно — с половиной веса
+		{"parent for child", map[string]int{"JavaScript": 4}, "React", 0.7 * 0.4, "🔗 React: близкий навык: JavaScript, уровень 4"},
+		// Уровень ниже требуемого уменьшает вклад пропорционально
+		{"lower level", map[string]int{"React": 2}, "JavaScript", 0.5 * 0.7 * 0.8, "🔗 JavaScript: близкий навык: React, уровень 2"},
+		// Из нескольких близких навыков берется лучший
+		{"best related", map[string]int{"Vue": 4, "Angular": 4}, "React", 0.7 * 0.6, "🔗 React: близкий навык: Vue, уровень 4"},
+		// Связи не транзитивны: React Native → React → JavaScript не засчитывается
+		{"not transitive", map[string]int{"React Native": 4}, "JavaScript", 0, "❌ JavaScript: навык отсутствует"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.UserProfile{Skills: map[string]models.SkillLevel{}}
+			for name, level := range tt.have {
+				user.Skills[name] = models.SkillLevel{Name: name, Level: level}
+			}
+			task := &models.TaskProfile{RequiredSkills: map[string]int{tt.need: 4}}
+
+			score, reasons, ok := factor.Score(user, task)
+			if !ok || math.Abs(score-tt.want) > 1e-9 {
+				t.Errorf("Score = %g, %v; want %g", score, ok, tt.want)
+			}
+			if !slices.Contains(reasons, tt.reason) {
+				t.Errorf("reasons = %q, want %q", reasons, tt.reason)
+			}
+		})
+	}
+}
+
+func TestSkillsFactorWithoutGraph(t *testing.T) {
+	user := &models.UserProfile{Skills: map[string]models.SkillLevel{"React": {Name: "React", Level: 5}}}
+	task := &models.TaskProfile{RequiredSkills: map[string]int{"JavaScript": 3}}
+
+	if score, _, _ := (skillsFactor{}).Score(user, task); score != 0 {
+		t.Errorf("Score without graph = %g, want 0", score)
+	}
+}
+
+func TestRelatedSkillCountsLessThanExact(t *testing.T) {
+	m := newTestMatcher(t, map[string]float64{FactorSkills: 1}, 0.3)
+	task := &models.TaskProfile{ID: "t", Status: "open", RequiredSkills: map[string]int{"JavaScript": 3, "CSS": 3}}
+	exact := &models.UserProfile{ID: "exact", Skills: map[string]models.SkillLevel{
+		"JavaScript": {Name: "JavaScript", Level: 3}, "CSS": {Name: "CSS", Level: 3},
+	}}
+	related := &models.UserProfile{ID: "related", Skills: map[string]models.SkillLevel{
+		"TypeScript": {Name: "TypeScript", Level: 3}, "HTML": {Name: "HTML", Level: 3},
+	}}
+	missing := &models.UserProfile{ID: "missing", Skills: map[string]models.SkillLevel{
+		"Python": {Name: "Python", Level: 5},
+	}}
+
+	var order []string
+	for _, match := range m.FindCandidates(task, []*models.UserProfile{missing, related, exact}) {
+		order = append(order, match.UserID)
+	}
+	if !slices.Equal(order, []string{"exact", "related"}) {
+		t.Errorf("FindCandidates = %v, want exact before related and no missing", order)
+	}
+}
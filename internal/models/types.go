@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type UserProfile struct {
 	ID         string                `json:"id"`
@@ -14,13 +17,24 @@ type UserProfile struct {
 	Verified   map[string]bool       `json:"verified"`
 	CreatedAt  time.Time             `json:"created_at"`
 	UpdatedAt  time.Time             `json:"updated_at"`
+
+	// PromptVersion — промпт, которым профиль извлечен из интервью
+	PromptVersion string `json:"prompt_version,omitempty"`
+
+	// Сигналы для подбора из интервью; 0 — нет данных, и фактор не учитывается
+	Availability float64 `json:"availability,omitempty"` // 0-1, доля полной занятости, свободная для задач
+	MinBudget    int     `json:"min_budget,omitempty"`   // минимальный интересный бюджет, ₽
+
+	// Embedding — векторы интересов: Vectors[i] соответствует Interests[i]
+	Embedding *Embedding `json:"embedding,omitempty"`
 }
 
 type SkillLevel struct {
-	Name     string `json:"name"`
-	Level    int    `json:"level"` // 1-5
-	Verified bool   `json:"verified"`
-	Source   string `json:"source"` // interview, task, etc.
+	Name       string  `json:"name"`
+	Level      int     `json:"level"`                // 1-5
+	Confidence float64 `json:"confidence,omitempty"` // 0-1, насколько уверенно оценен уровень
+	Verified   bool    `json:"verified"`
+	Source     string  `json:"source"` // interview, task, etc.
 }
 
 type Experience struct {
@@ -41,6 +55,24 @@ type TaskProfile struct {
 	CreatedBy      string         `json:"created_by"`
 	Status         string         `json:"status"` // open, assigned, completed
 	CreatedAt      time.Time      `json:"created_at"`
+	PromptVersion  string         `json:"prompt_version,omitempty"`
+
+	// Embedding — векторы названия и фрагментов описания
+	Embedding *Embedding `json:"embedding,omitempty"`
+}
+
+// Embedding — векторные представления текстов для смыслового сравнения.
+// Model — чем они получены (векторы разных моделей несравнимы), Hash — от
+// каких текстов: при изменении текстов векторы пересчитываются.
+type Embedding struct {
+	Model   string      `json:"model"`
+	Hash    string      `json:"hash"`
+	Vectors [][]float32 `json:"vectors"`
+}
+
+// TaskAuthor — значение TaskProfile.CreatedBy для задач пользователя Telegram
+func TaskAuthor(telegramID int64) string {
+	return fmt.Sprintf("user_%d", telegramID)
 }
 
 type InterviewSession struct {
@@ -50,6 +82,70 @@ type InterviewSession struct {
 	Answers     map[string]interface{} `json:"answers"`
 	Context     map[string]interface{} `json:"context"`
 	StartedAt   time.Time              `json:"started_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	RemindedAt  time.Time              `json:"reminded_at"`
+
+	// Parsed — разобранные значения ответов (число, сумма, дата), ключ как в Answers
+	Parsed map[string]interface{} `json:"parsed,omitempty"`
+	// StepContext — вклад каждого ответа в Context, ключ как в Answers
+	StepContext map[string]map[string]interface{} `json:"step_context,omitempty"`
+	// History — вопросы интервью по позициям: History[n] — вопрос, ответ на который
+	// лежит в Answers["q_n"]. CurrentStep — текущая позиция, len(History) — интервью окончено.
+	History []string `json:"history,omitempty"`
+	// Selected — отмеченные варианты текущего вопроса с множественным выбором
+	Selected []int `json:"selected,omitempty"`
+
+	// Mode — "chat", если интервью ведет модель в форме беседы; тогда
+	// CurrentStep — число ответов, а вопросы и ответы лежат в Transcript
+	Mode       string        `json:"mode,omitempty"`
+	Transcript []ChatMessage `json:"transcript,omitempty"`
+	// Tokens — оценка токенов, израсходованных на беседу
+	Tokens int `json:"tokens,omitempty"`
+	// ChatDone — беседа окончена, осталось извлечь профиль
+	ChatDone bool `json:"chat_done,omitempty"`
+}
+
+// ChatMessage — реплика беседы: role "assistant" (интервьюер) или "user"
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// LastActivity — время последнего ответа (или начала интервью)
+func (s *InterviewSession) LastActivity() time.Time {
+	if s.UpdatedAt.After(s.StartedAt) {
+		return s.UpdatedAt
+	}
+	return s.StartedAt
+}
+
+// Clone возвращает копию сессии, которую можно читать без блокировок
+func (s *InterviewSession) Clone() *InterviewSession {
+	c := *s
+	c.Answers = cloneMap(s.Answers)
+	c.Context = cloneMap(s.Context)
+	c.Parsed = cloneMap(s.Parsed)
+	if s.StepContext != nil {
+		c.StepContext = make(map[string]map[string]interface{}, len(s.StepContext))
+		for k, v := range s.StepContext {
+			c.StepContext[k] = cloneMap(v)
+		}
+	}
+	c.History = append([]string(nil), s.History...)
+	c.Selected = append([]int(nil), s.Selected...)
+	c.Transcript = append([]ChatMessage(nil), s.Transcript...)
+	return &c
+}
+
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
 }
 
 type MatchResult struct {
@@ -59,3 +155,20 @@ type MatchResult struct {
 	Reasons   []string  `json:"reasons"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// SkillAlias — написание навыка, которое администратор сопоставил каноническому навыку
+type SkillAlias struct {
+	Alias     string    `json:"alias"`
+	Skill     string    `json:"skill"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UnknownSkill — навык, которого нет в таксономии; ждет решения администратора.
+// Key — нормализованное написание, Name — как навык написали в первый раз.
+type UnknownSkill struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
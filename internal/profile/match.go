@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"sort"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+type matchKey struct {
+	taskID string
+	userID string
+}
+
+func (s *InMemoryStorage) SaveMatch(match *models.MatchResult) error {
+	if match == nil || match.TaskID == "" || match.UserID == "" {
+		return ErrInvalidMatch
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if match.CreatedAt.IsZero() {
+		match.CreatedAt = time.Now()
+	}
+	s.matches[matchKey{taskID: match.TaskID, userID: match.UserID}] = *match
+	return nil
+}
+
+func (s *InMemoryStorage) GetMatchesForUser(userID string) []models.MatchResult {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var matches []models.MatchResult
+	for key, m := range s.matches {
+		if key.userID == userID {
+			matches = append(matches, m)
+		}
+	}
+	sortMatches(matches)
+	return matches
+}
+
+func (s *InMemoryStorage) GetMatchesForTask(taskID string) []models.MatchResult {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var matches []models.MatchResult
+	for key, m := range s.matches {
+		if key.taskID == taskID {
+			matches = append(matches, m)
+		}
+	}
+	sortMatches(matches)
+	return matches
+}
+
+func sortMatches(matches []models.MatchResult) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].TaskID != matches[j].TaskID {
+			return matches[i].TaskID < matches[j].TaskID
+		}
+		return matches[i].UserID < matches[j].UserID
+	})
+}
@@ -0,0 +1,180 @@
+package profile
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Миграции применяются по порядку и никогда не редактируются задним числом —
+// изменения схемы добавляются новой записью в конец списка.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "initial schema",
+		sql: `
+CREATE TABLE users (
+	id          TEXT PRIMARY KEY,
+	telegram_id INTEGER NOT NULL DEFAULT 0,
+	name        TEXT NOT NULL DEFAULT '',
+	interests   TEXT NOT NULL DEFAULT '[]',
+	soft_skills TEXT NOT NULL DEFAULT '[]',
+	goals       TEXT NOT NULL DEFAULT '[]',
+	verified    TEXT NOT NULL DEFAULT '{}',
+	created_at  DATETIME NOT NULL,
+	updated_at  DATETIME NOT NULL
+);
+
+CREATE TABLE user_skills (
+	user_id  TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	skill    TEXT NOT NULL,
+	name     TEXT NOT NULL DEFAULT '',
+	level    INTEGER NOT NULL DEFAULT 0,
+	verified INTEGER NOT NULL DEFAULT 0,
+	source   TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (user_id, skill)
+);
+
+CREATE TABLE user_experience (
+	user_id     TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	position_no INTEGER NOT NULL,
+	company     TEXT NOT NULL DEFAULT '',
+	position    TEXT NOT NULL DEFAULT '',
+	duration    TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	skills      TEXT NOT NULL DEFAULT '[]',
+	PRIMARY KEY (user_id, position_no)
+);
+
+CREATE TABLE tasks (
+	id          TEXT PRIMARY KEY,
+	title       TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	budget      INTEGER NOT NULL DEFAULT 0,
+	deadline    DATETIME NOT NULL,
+	created_by  TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL DEFAULT '',
+	created_at  DATETIME NOT NULL
+);
+
+CREATE INDEX tasks_status ON tasks(status);
+
+CREATE TABLE task_skills (
+	task_id   TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	skill     TEXT NOT NULL,
+	min_level INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (task_id, skill)
+);`,
+	},
+	{
+		version: 2,
+		name:    "matches and interview sessions",
+		sql: `
+CREATE TABLE matches (
+	task_id    TEXT NOT NULL,
+	user_id    TEXT NOT NULL,
+	score      REAL NOT NULL,
+	reasons    TEXT NOT NULL DEFAULT '[]',
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX matches_user ON matches(user_id);
+
+-- Сессия хранится целиком в JSON, чтобы новые поля не требовали миграций
+CREATE TABLE interview_sessions (
+	user_id    INTEGER PRIMARY KEY,
+	type       TEXT NOT NULL,
+	data       TEXT NOT NULL,
+	started_at DATETIME NOT NULL
+);`,
+	},
+	{
+		version: 3,
+		name:    "skill confidence",
+		sql:     `ALTER TABLE user_skills ADD COLUMN confidence REAL NOT NULL DEFAULT 0;`,
+	},
+	{
+		version: 4,
+		name:    "prompt versions",
+		sql: `
+ALTER TABLE users ADD COLUMN prompt_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN prompt_version TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		version: 5,
+		name:    "matching signals",
+		sql: `
+ALTER TABLE users ADD COLUMN availability REAL NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN min_budget INTEGER NOT NULL DEFAULT 0;`,
+	},
+	{
+		version: 6,
+		name:    "skill taxonomy",
+		sql: `
+CREATE TABLE skill_aliases (
+	alias      TEXT PRIMARY KEY,
+	skill      TEXT NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE TABLE unknown_skills (
+	key        TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	count      INTEGER NOT NULL DEFAULT 0,
+	first_seen DATETIME NOT NULL,
+	last_seen  DATETIME NOT NULL
+);
+CREATE INDEX unknown_skills_count ON unknown_skills(count);`,
+	},
+	{
+		version: 7,
+		name:    "embeddings",
+		sql: `
+ALTER TABLE users ADD COLUMN embedding TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN embedding TEXT NOT NULL DEFAULT '';`,
+	},
+}
+
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m.sql); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.version, m.name); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+	}
+
+	return nil
+}
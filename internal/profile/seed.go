@@ -0,0 +1,70 @@
+package profile
+
+import (
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+// SeedDemoTasks добавляет в хранилище открытые задачи для демонстрации подбора.
+// Задачи, которые уже есть (например, в базе после перезапуска), не перезаписываются.
+func SeedDemoTasks(store Store) error {
+	now := time.Now()
+	demoTasks := []*models.TaskProfile{
+		{
+			ID:          "task_1",
+			Title:       "Создать простой веб-сайт на React",
+			Description: "Нужно сделать лендинг для стартапа с современным дизайном",
+			RequiredSkills: map[string]int{
+				"React":      2,
+				"JavaScript": 3,
+				"CSS":        2,
+			},
+			Budget:    30000,
+			Deadline:  now.AddDate(0, 0, 14),
+			CreatedBy: "client_1",
+			Status:    "open",
+			CreatedAt: now,
+		},
+		{
+			ID:          "task_2",
+			Title:       "Парсер данных на Python",
+			Description: "Написать скрипт для сбора данных с сайтов и сохранения в БД",
+			RequiredSkills: map[string]int{
+				"Python":        3,
+				"BeautifulSoup": 2,
+				"SQL":           2,
+			},
+			Budget:    25000,
+			Deadline:  now.AddDate(0, 0, 7),
+			CreatedBy: "client_2",
+			Status:    "open",
+			CreatedAt: now,
+		},
+		{
+			ID:          "task_3",
+			Title:       "Мобильное приложение на Flutter",
+			Description: "Простое приложение для заметок с синхронизацией",
+			RequiredSkills: map[string]int{
+				"Flutter":  3,
+				"Dart":     3,
+				"Firebase": 2,
+			},
+			Budget:    80000,
+			Deadline:  now.AddDate(0, 1, 0),
+			CreatedBy: "client_3",
+			Status:    "open",
+			CreatedAt: now,
+		},
+	}
+
+	for _, task := range demoTasks {
+		if store.GetTask(task.ID) != nil {
+			continue
+		}
+		if err := store.SaveTask(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package profile
+
+import "viget-mvp/internal/models"
+
+func (s *InMemoryStorage) SaveSession(session *models.InterviewSession) error {
+	if session == nil || session.UserID == 0 {
+		return ErrInvalidSession
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sessions[session.UserID] = session
+	return nil
+}
+
+func (s *InMemoryStorage) GetSession(userID int64) *models.InterviewSession {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.sessions[userID]
+}
+
+func (s *InMemoryStorage) DeleteSession(userID int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.sessions[userID]; !ok {
+		return ErrSessionNotFound
+	}
+	delete(s.sessions, userID)
+	return nil
+}
+
+func (s *InMemoryStorage) ListSessions() []*models.InterviewSession {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	sessions := make([]*models.InterviewSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
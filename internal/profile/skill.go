@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"sort"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+func (s *InMemoryStorage) SaveSkillAlias(alias, skill string) error {
+	if alias == "" || skill == "" {
+		return ErrInvalidSkill
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, a := range s.aliases {
+		if a.Alias == alias {
+			s.aliases = append(s.aliases[:i], s.aliases[i+1:]...)
+			break
+		}
+	}
+	s.aliases = append(s.aliases, models.SkillAlias{Alias: alias, Skill: skill, CreatedAt: time.Now()})
+	return nil
+}
+
+func (s *InMemoryStorage) ListSkillAliases() []models.SkillAlias {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]models.SkillAlias(nil), s.aliases...)
+}
+
+func (s *InMemoryStorage) RecordUnknownSkill(key, name string, seenAt time.Time) error {
+	if key == "" || name == "" {
+		return ErrInvalidSkill
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	skill, ok := s.unknownSkills[key]
+	if !ok {
+		skill = models.UnknownSkill{Key: key, Name: name, FirstSeen: seenAt}
+	}
+	skill.Count++
+	skill.LastSeen = seenAt
+	s.unknownSkills[key] = skill
+	return nil
+}
+
+func (s *InMemoryStorage) ListUnknownSkills() []models.UnknownSkill {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	skills := make([]models.UnknownSkill, 0, len(s.unknownSkills))
+	for _, skill := range s.unknownSkills {
+		skills = append(skills, skill)
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Count != skills[j].Count {
+			return skills[i].Count > skills[j].Count
+		}
+		return skills[i].Key < skills[j].Key
+	})
+	return skills
+}
+
+func (s *InMemoryStorage) DeleteUnknownSkill(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.unknownSkills[key]; !ok {
+		return ErrSkillNotFound
+	}
+	delete(s.unknownSkills, key)
+	return nil
+}
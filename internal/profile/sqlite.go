@@ -0,0 +1,499 @@
+// internal/profile/sqlite.go
+package profile
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"viget-mvp/internal/models"
+)
+
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite не любит параллельных писателей, одного соединения для бота достаточно
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStorage{db: db}, nil
+}
+
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStorage) SaveUserProfile(profile *models.UserProfile) error {
+	profile.UpdatedAt = time.Now()
+
+	interests, err := json.Marshal(profile.Interests)
+	if err != nil {
+		return err
+	}
+	softSkills, err := json.Marshal(profile.SoftSkills)
+	if err != nil {
+		return err
+	}
+	goals, err := json.Marshal(profile.Goals)
+	if err != nil {
+		return err
+	}
+	verified, err := json.Marshal(profile.Verified)
+	if err != nil {
+		return err
+	}
+	embedding, err := encodeEmbedding(profile.Embedding)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+INSERT INTO users (id, telegram_id, name, interests, soft_skills, goals, verified, created_at, updated_at, prompt_version,
+	availability, min_budget, embedding)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	telegram_id = excluded.telegram_id,
+	name        = excluded.name,
+	interests   = excluded.interests,
+	soft_skills = excluded.soft_skills,
+	goals       = excluded.goals,
+	verified    = excluded.verified,
+	created_at  = excluded.created_at,
+	updated_at  = excluded.updated_at,
+	prompt_version = excluded.prompt_version,
+	availability = excluded.availability,
+	min_budget   = excluded.min_budget,
+	embedding    = excluded.embedding`,
+		profile.ID, profile.TelegramID, profile.Name,
+		string(interests), string(softSkills), string(goals), string(verified),
+		profile.CreatedAt, profile.UpdatedAt, profile.PromptVersion,
+		profile.Availability, profile.MinBudget, embedding)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_skills WHERE user_id = ?`, profile.ID); err != nil {
+		return err
+	}
+	for key, skill := range profile.Skills {
+		_, err := tx.Exec(`INSERT INTO user_skills (user_id, skill, name, level, confidence, verified, source) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			profile.ID, key, skill.Name, skill.Level, skill.Confidence, skill.Verified, skill.Source)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_experience WHERE user_id = ?`, profile.ID); err != nil {
+		return err
+	}
+	for i, exp := range profile.Experience {
+		skills, err := json.Marshal(exp.Skills)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO user_experience (user_id, position_no, company, position, duration, description, skills) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			profile.ID, i, exp.Company, exp.Position, exp.Duration, exp.Description, string(skills))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) GetUserProfile(userID string) *models.UserProfile {
+	profile, err := s.loadUserProfile(userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("sqlite: load user %s: %v", userID, err)
+		}
+		return nil
+	}
+	return profile
+}
+
+func (s *SQLiteStorage) loadUserProfile(userID string) (*models.UserProfile, error) {
+	profile := &models.UserProfile{}
+	var interests, softSkills, goals, verified, embedding string
+
+	err := s.db.QueryRow(`
+SELECT id, telegram_id, name, interests, soft_skills, goals, verified, created_at, updated_at, prompt_version,
+	availability, min_budget, embedding
+FROM users WHERE id = ?`, userID).Scan(
+		&profile.ID, &profile.TelegramID, &profile.Name,
+		&interests, &softSkills, &goals, &verified,
+		&profile.CreatedAt, &profile.UpdatedAt, &profile.PromptVersion,
+		&profile.Availability, &profile.MinBudget, &embedding)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Embedding, err = decodeEmbedding(embedding); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(interests), &profile.Interests); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(softSkills), &profile.SoftSkills); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(goals), &profile.Goals); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(verified), &profile.Verified); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT skill, name, level, confidence, verified, source FROM user_skills WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profile.Skills = make(map[string]models.SkillLevel)
+	for rows.Next() {
+		var key string
+		var skill models.SkillLevel
+		if err := rows.Scan(&key, &skill.Name, &skill.Level, &skill.Confidence, &skill.Verified, &skill.Source); err != nil {
+			return nil, err
+		}
+		profile.Skills[key] = skill
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	expRows, err := s.db.Query(`
+SELECT company, position, duration, description, skills
+FROM user_experience WHERE user_id = ? ORDER BY position_no`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer expRows.Close()
+
+	for expRows.Next() {
+		var exp models.Experience
+		var skills string
+		if err := expRows.Scan(&exp.Company, &exp.Position, &exp.Duration, &exp.Description, &skills); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(skills), &exp.Skills); err != nil {
+			return nil, err
+		}
+		profile.Experience = append(profile.Experience, exp)
+	}
+
+	return profile, expRows.Err()
+}
+
+func (s *SQLiteStorage) CreateUserProfile(profile *models.UserProfile) error {
+	if profile == nil || profile.ID == "" {
+		return ErrInvalidProfile
+	}
+	return s.SaveUserProfile(profile)
+}
+
+func (s *SQLiteStorage) GetUserProfileByID(userID string) (*models.UserProfile, error) {
+	profile := s.GetUserProfile(userID)
+	if profile == nil {
+		return nil, ErrUserNotFound
+	}
+	return profile, nil
+}
+
+func (s *SQLiteStorage) UpdateUserProfile(profile *models.UserProfile) error {
+	if profile == nil || profile.ID == "" {
+		return ErrInvalidProfile
+	}
+	return s.SaveUserProfile(profile)
+}
+
+func (s *SQLiteStorage) DeleteUserProfile(userID string) error {
+	res, err := s.db.Exec(`DELETE FROM users WHERE id = ?`, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) SaveUserEmbedding(userID string, embedding *models.Embedding) error {
+	data, err := encodeEmbedding(embedding)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.Exec(`UPDATE users SET embedding = ? WHERE id = ?`, data, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) SaveTask(task *models.TaskProfile) error {
+	embedding, err := encodeEmbedding(task.Embedding)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+INSERT INTO tasks (id, title, description, budget, deadline, created_by, status, created_at, prompt_version, embedding)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	title       = excluded.title,
+	description = excluded.description,
+	budget      = excluded.budget,
+	deadline    = excluded.deadline,
+	created_by  = excluded.created_by,
+	status      = excluded.status,
+	created_at  = excluded.created_at,
+	prompt_version = excluded.prompt_version,
+	embedding   = excluded.embedding`,
+		task.ID, task.Title, task.Description, task.Budget, task.Deadline,
+		task.CreatedBy, task.Status, task.CreatedAt, task.PromptVersion, embedding)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_skills WHERE task_id = ?`, task.ID); err != nil {
+		return err
+	}
+	for skill, minLevel := range task.RequiredSkills {
+		_, err := tx.Exec(`INSERT INTO task_skills (task_id, skill, min_level) VALUES (?, ?, ?)`,
+			task.ID, skill, minLevel)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) GetTask(taskID string) *models.TaskProfile {
+	tasks, err := s.queryTasks(`WHERE id = ?`, taskID)
+	if err != nil {
+		log.Printf("sqlite: load task %s: %v", taskID, err)
+		return nil
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	return tasks[0]
+}
+
+func (s *SQLiteStorage) GetAvailableTasks() []*models.TaskProfile {
+	tasks, err := s.queryTasks(`WHERE status = ?`, "open")
+	if err != nil {
+		log.Printf("sqlite: load available tasks: %v", err)
+		return nil
+	}
+	return tasks
+}
+
+func (s *SQLiteStorage) CreateTask(task *models.TaskProfile) error {
+	if task == nil || task.ID == "" {
+		return ErrInvalidTask
+	}
+	return s.SaveTask(task)
+}
+
+func (s *SQLiteStorage) GetTaskByID(taskID string) (*models.TaskProfile, error) {
+	task := s.GetTask(taskID)
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (s *SQLiteStorage) UpdateTask(task *models.TaskProfile) error {
+	if task == nil || task.ID == "" {
+		return ErrInvalidTask
+	}
+	return s.SaveTask(task)
+}
+
+func (s *SQLiteStorage) DeleteTask(taskID string) error {
+	res, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, taskID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) SaveTaskEmbedding(taskID string, embedding *models.Embedding) error {
+	data, err := encodeEmbedding(embedding)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.Exec(`UPDATE tasks SET embedding = ? WHERE id = ?`, data, taskID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) ListTasks() []*models.TaskProfile {
+	tasks, err := s.queryTasks("")
+	if err != nil {
+		log.Printf("sqlite: list tasks: %v", err)
+		return nil
+	}
+	if tasks == nil {
+		tasks = make([]*models.TaskProfile, 0)
+	}
+	return tasks
+}
+
+// queryTasks загружает задачи вместе с требуемыми навыками.
+// where подставляется как есть, значения передаются через args.
+func (s *SQLiteStorage) queryTasks(where string, args ...interface{}) ([]*models.TaskProfile, error) {
+	rows, err := s.db.Query(`
+SELECT id, title, description, budget, deadline, created_by, status, created_at, prompt_version, embedding
+FROM tasks `+where+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*models.TaskProfile
+	for rows.Next() {
+		task := &models.TaskProfile{}
+		var embedding string
+		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Budget,
+			&task.Deadline, &task.CreatedBy, &task.Status, &task.CreatedAt, &task.PromptVersion, &embedding)
+		if err != nil {
+			return nil, err
+		}
+		if task.Embedding, err = decodeEmbedding(embedding); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, task := range tasks {
+		skillRows, err := s.db.Query(`SELECT skill, min_level FROM task_skills WHERE task_id = ?`, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		for skillRows.Next() {
+			var skill string
+			var minLevel int
+			if err := skillRows.Scan(&skill, &minLevel); err != nil {
+				skillRows.Close()
+				return nil, err
+			}
+			if task.RequiredSkills == nil {
+				task.RequiredSkills = make(map[string]int)
+			}
+			task.RequiredSkills[skill] = minLevel
+		}
+		err = skillRows.Err()
+		skillRows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, nil
+}
+
+func (s *SQLiteStorage) ListUserProfiles() []*models.UserProfile {
+	rows, err := s.db.Query(`SELECT id FROM users ORDER BY created_at, id`)
+	if err != nil {
+		log.Printf("sqlite: list users: %v", err)
+		return nil
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			log.Printf("sqlite: list users: %v", err)
+			return nil
+		}
+		ids = append(ids, id)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		log.Printf("sqlite: list users: %v", err)
+		return nil
+	}
+
+	users := make([]*models.UserProfile, 0, len(ids))
+	for _, id := range ids {
+		if profile := s.GetUserProfile(id); profile != nil {
+			users = append(users, profile)
+		}
+	}
+	return users
+}
+
+// encodeEmbedding хранит векторы JSON-строкой; пустая строка — векторов нет
+func encodeEmbedding(e *models.Embedding) (string, error) {
+	if e == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(e)
+	return string(data), err
+}
+
+func decodeEmbedding(data string) (*models.Embedding, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var e models.Embedding
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
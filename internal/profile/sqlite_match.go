@@ -0,0 +1,75 @@
+package profile
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+func (s *SQLiteStorage) SaveMatch(match *models.MatchResult) error {
+	if match == nil || match.TaskID == "" || match.UserID == "" {
+		return ErrInvalidMatch
+	}
+
+	if match.CreatedAt.IsZero() {
+		match.CreatedAt = time.Now()
+	}
+	reasons, err := json.Marshal(match.Reasons)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+INSERT INTO matches (task_id, user_id, score, reasons, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(task_id, user_id) DO UPDATE SET
+	score      = excluded.score,
+	reasons    = excluded.reasons,
+	created_at = excluded.created_at`,
+		match.TaskID, match.UserID, match.Score, string(reasons), match.CreatedAt)
+	return err
+}
+
+func (s *SQLiteStorage) GetMatchesForUser(userID string) []models.MatchResult {
+	matches, err := s.queryMatches(`WHERE user_id = ?`, userID)
+	if err != nil {
+		log.Printf("sqlite: load matches for user %s: %v", userID, err)
+		return nil
+	}
+	return matches
+}
+
+func (s *SQLiteStorage) GetMatchesForTask(taskID string) []models.MatchResult {
+	matches, err := s.queryMatches(`WHERE task_id = ?`, taskID)
+	if err != nil {
+		log.Printf("sqlite: load matches for task %s: %v", taskID, err)
+		return nil
+	}
+	return matches
+}
+
+func (s *SQLiteStorage) queryMatches(where string, args ...interface{}) ([]models.MatchResult, error) {
+	rows, err := s.db.Query(`
+SELECT task_id, user_id, score, reasons, created_at
+FROM matches `+where+` ORDER BY score DESC, task_id, user_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []models.MatchResult
+	for rows.Next() {
+		var m models.MatchResult
+		var reasons string
+		if err := rows.Scan(&m.TaskID, &m.UserID, &m.Score, &reasons, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(reasons), &m.Reasons); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
@@ -0,0 +1,104 @@
+package profile
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"viget-mvp/internal/models"
+)
+
+func (s *SQLiteStorage) SaveSession(session *models.InterviewSession) error {
+	if session == nil || session.UserID == 0 {
+		return ErrInvalidSession
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+INSERT INTO interview_sessions (user_id, type, data, started_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(user_id) DO UPDATE SET
+	type       = excluded.type,
+	data       = excluded.data,
+	started_at = excluded.started_at`,
+		session.UserID, session.Type, string(data), session.StartedAt)
+	return err
+}
+
+func (s *SQLiteStorage) GetSession(userID int64) *models.InterviewSession {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM interview_sessions WHERE user_id = ?`, userID).Scan(&data)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("sqlite: load session %d: %v", userID, err)
+		}
+		return nil
+	}
+
+	session, err := decodeSession(data)
+	if err != nil {
+		log.Printf("sqlite: decode session %d: %v", userID, err)
+		return nil
+	}
+	return session
+}
+
+func (s *SQLiteStorage) DeleteSession(userID int64) error {
+	res, err := s.db.Exec(`DELETE FROM interview_sessions WHERE user_id = ?`, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) ListSessions() []*models.InterviewSession {
+	rows, err := s.db.Query(`SELECT user_id, data FROM interview_sessions ORDER BY started_at, user_id`)
+	if err != nil {
+		log.Printf("sqlite: list sessions: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.InterviewSession, 0)
+	for rows.Next() {
+		var userID int64
+		var data string
+		if err := rows.Scan(&userID, &data); err != nil {
+			log.Printf("sqlite: list sessions: %v", err)
+			return nil
+		}
+		session, err := decodeSession(data)
+		if err != nil {
+			log.Printf("sqlite: decode session %d: %v", userID, err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("sqlite: list sessions: %v", err)
+		return nil
+	}
+	return sessions
+}
+
+func decodeSession(data string) (*models.InterviewSession, error) {
+	var session models.InterviewSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, err
+	}
+	if session.Answers == nil {
+		session.Answers = make(map[string]interface{})
+	}
+	if session.Context == nil {
+		session.Context = make(map[string]interface{})
+	}
+	return &session, nil
+}
@@ -0,0 +1,100 @@
+package profile
+
+import (
+	"log"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+func (s *SQLiteStorage) SaveSkillAlias(alias, skill string) error {
+	if alias == "" || skill == "" {
+		return ErrInvalidSkill
+	}
+
+	// Перезаписанный синоним переезжает в конец: порядок важен при загрузке таксономии
+	_, err := s.db.Exec(`
+INSERT INTO skill_aliases (alias, skill, created_at)
+VALUES (?, ?, ?)
+ON CONFLICT(alias) DO UPDATE SET
+	skill      = excluded.skill,
+	created_at = excluded.created_at`,
+		alias, skill, time.Now())
+	return err
+}
+
+func (s *SQLiteStorage) ListSkillAliases() []models.SkillAlias {
+	rows, err := s.db.Query(`SELECT alias, skill, created_at FROM skill_aliases ORDER BY created_at, alias`)
+	if err != nil {
+		log.Printf("sqlite: list skill aliases: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var aliases []models.SkillAlias
+	for rows.Next() {
+		var a models.SkillAlias
+		if err := rows.Scan(&a.Alias, &a.Skill, &a.CreatedAt); err != nil {
+			log.Printf("sqlite: list skill aliases: %v", err)
+			return nil
+		}
+		aliases = append(aliases, a)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("sqlite: list skill aliases: %v", err)
+		return nil
+	}
+	return aliases
+}
+
+func (s *SQLiteStorage) RecordUnknownSkill(key, name string, seenAt time.Time) error {
+	if key == "" || name == "" {
+		return ErrInvalidSkill
+	}
+
+	_, err := s.db.Exec(`
+INSERT INTO unknown_skills (key, name, count, first_seen, last_seen)
+VALUES (?, ?, 1, ?, ?)
+ON CONFLICT(key) DO UPDATE SET
+	count     = count + 1,
+	last_seen = excluded.last_seen`,
+		key, name, seenAt, seenAt)
+	return err
+}
+
+func (s *SQLiteStorage) ListUnknownSkills() []models.UnknownSkill {
+	rows, err := s.db.Query(`
+SELECT key, name, count, first_seen, last_seen
+FROM unknown_skills ORDER BY count DESC, key`)
+	if err != nil {
+		log.Printf("sqlite: list unknown skills: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	skills := make([]models.UnknownSkill, 0)
+	for rows.Next() {
+		var u models.UnknownSkill
+		if err := rows.Scan(&u.Key, &u.Name, &u.Count, &u.FirstSeen, &u.LastSeen); err != nil {
+			log.Printf("sqlite: list unknown skills: %v", err)
+			return nil
+		}
+		skills = append(skills, u)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("sqlite: list unknown skills: %v", err)
+		return nil
+	}
+	return skills
+}
+
+func (s *SQLiteStorage) DeleteUnknownSkill(key string) error {
+	res, err := s.db.Exec(`DELETE FROM unknown_skills WHERE key = ?`, key)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSkillNotFound
+	}
+	return nil
+}
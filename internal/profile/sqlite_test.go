@@ -0,0 +1,20 @@
+package profile_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"viget-mvp/internal/profile"
+	"viget-mvp/internal/profile/storetest"
+)
+
+func TestSQLiteStorage(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) profile.Store {
+		s, err := profile.NewSQLiteStorage(filepath.Join(t.TempDir(), "viget.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteStorage: %v", err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
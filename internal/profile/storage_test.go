@@ -0,0 +1,14 @@
+package profile_test
+
+import (
+	"testing"
+
+	"viget-mvp/internal/profile"
+	"viget-mvp/internal/profile/storetest"
+)
+
+func TestInMemoryStorage(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) profile.Store {
+		return profile.NewInMemoryStorage()
+	})
+}
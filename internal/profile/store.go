@@ -0,0 +1,94 @@
+// internal/profile/store.go
+package profile
+
+import (
+	"errors"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+var (
+	ErrInvalidProfile  = errors.New("invalid profile")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrInvalidTask     = errors.New("invalid task")
+	ErrTaskNotFound    = errors.New("task not found")
+	ErrInvalidMatch    = errors.New("invalid match")
+	ErrInvalidSession  = errors.New("invalid session")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrInvalidSkill    = errors.New("invalid skill")
+	ErrSkillNotFound   = errors.New("skill not found")
+)
+
+// Store — общий контракт хранилища. Все реализации (память, SQLite)
+// обязаны проходить набор проверок из пакета storetest.
+type Store interface {
+	UserStore
+	TaskStore
+	MatchStore
+	SessionStore
+	SkillStore
+}
+
+type UserStore interface {
+	SaveUserProfile(profile *models.UserProfile) error
+	// GetUserProfile возвращает nil, если профиль не найден
+	GetUserProfile(userID string) *models.UserProfile
+	CreateUserProfile(profile *models.UserProfile) error
+	GetUserProfileByID(userID string) (*models.UserProfile, error)
+	UpdateUserProfile(profile *models.UserProfile) error
+	DeleteUserProfile(userID string) error
+	ListUserProfiles() []*models.UserProfile
+	// SaveUserEmbedding заменяет только векторы профиля; UpdatedAt не меняется
+	SaveUserEmbedding(userID string, embedding *models.Embedding) error
+}
+
+type TaskStore interface {
+	SaveTask(task *models.TaskProfile) error
+	// GetTask возвращает nil, если задача не найдена
+	GetTask(taskID string) *models.TaskProfile
+	GetAvailableTasks() []*models.TaskProfile
+	CreateTask(task *models.TaskProfile) error
+	GetTaskByID(taskID string) (*models.TaskProfile, error)
+	UpdateTask(task *models.TaskProfile) error
+	DeleteTask(taskID string) error
+	ListTasks() []*models.TaskProfile
+	// SaveTaskEmbedding заменяет только векторы задачи
+	SaveTaskEmbedding(taskID string, embedding *models.Embedding) error
+}
+
+// MatchStore хранит последние рассчитанные совпадения.
+// Пара (TaskID, UserID) уникальна, повторное сохранение перезаписывает результат.
+type MatchStore interface {
+	SaveMatch(match *models.MatchResult) error
+	// GetMatchesForUser и GetMatchesForTask сортируют результаты по убыванию Score
+	GetMatchesForUser(userID string) []models.MatchResult
+	GetMatchesForTask(taskID string) []models.MatchResult
+}
+
+type SessionStore interface {
+	SaveSession(session *models.InterviewSession) error
+	// GetSession возвращает nil, если сессии нет
+	GetSession(userID int64) *models.InterviewSession
+	DeleteSession(userID int64) error
+	ListSessions() []*models.InterviewSession
+}
+
+// SkillStore хранит правки таксономии навыков: синонимы, добавленные
+// администратором, и незнакомые навыки, которые еще не разобраны.
+type SkillStore interface {
+	// SaveSkillAlias сопоставляет написание alias навыку skill; повторное сохранение перезаписывает
+	SaveSkillAlias(alias, skill string) error
+	// ListSkillAliases возвращает синонимы в порядке добавления
+	ListSkillAliases() []models.SkillAlias
+	// RecordUnknownSkill учитывает еще одно упоминание незнакомого навыка
+	RecordUnknownSkill(key, name string, seenAt time.Time) error
+	// ListUnknownSkills сортирует навыки по убыванию числа упоминаний
+	ListUnknownSkills() []models.UnknownSkill
+	DeleteUnknownSkill(key string) error
+}
+
+var (
+	_ Store = (*InMemoryStorage)(nil)
+	_ Store = (*SQLiteStorage)(nil)
+)
@@ -0,0 +1,512 @@
+// Package storetest содержит общий набор проверок для реализаций profile.Store.
+//
+// Каждый бэкенд подключает его из своего теста:
+//
+//	func TestSQLiteStorage(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) profile.Store { ... })
+//	}
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+)
+
+// Run прогоняет проверки на свежем, пустом хранилище для каждого подтеста
+func Run(t *testing.T, newStore func(t *testing.T) profile.Store) {
+	t.Run("UserRoundTrip", func(t *testing.T) { testUserRoundTrip(t, newStore(t)) })
+	t.Run("UserErrors", func(t *testing.T) { testUserErrors(t, newStore(t)) })
+	t.Run("TaskRoundTrip", func(t *testing.T) { testTaskRoundTrip(t, newStore(t)) })
+	t.Run("TaskErrors", func(t *testing.T) { testTaskErrors(t, newStore(t)) })
+	t.Run("Embeddings", func(t *testing.T) { testEmbeddings(t, newStore(t)) })
+	t.Run("AvailableTasks", func(t *testing.T) { testAvailableTasks(t, newStore(t)) })
+	t.Run("Matches", func(t *testing.T) { testMatches(t, newStore(t)) })
+	t.Run("Sessions", func(t *testing.T) { testSessions(t, newStore(t)) })
+	t.Run("Skills", func(t *testing.T) { testSkills(t, newStore(t)) })
+	t.Run("SeedDemoTasks", func(t *testing.T) { testSeedDemoTasks(t, newStore(t)) })
+}
+
+func testUserRoundTrip(t *testing.T, s profile.Store) {
+	created := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	user := &models.UserProfile{
+		ID:         "storetest_user",
+		TelegramID: 42,
+		Name:       "Анна",
+		Skills: map[string]models.SkillLevel{
+			"Go":     {Name: "Go", Level: 4, Confidence: 0.75, Verified: true, Source: "interview"},
+			"Python": {Name: "Python", Level: 2, Source: "task"},
+		},
+		Interests:  []string{"веб-разработка", "данные"},
+		SoftSkills: []string{"коммуникация"},
+		Goals:      []string{"стать senior"},
+		Experience: []models.Experience{
+			{Company: "ООО Пример", Position: "Junior", Duration: "6 месяцев", Description: "бэкенд", Skills: []string{"Go", "SQL"}},
+			{Company: "Фриланс", Position: "Разработчик", Skills: []string{"Python"}},
+		},
+		Verified:      map[string]bool{"Go": true},
+		CreatedAt:     created,
+		PromptVersion: "profile/v2",
+		Availability:  0.5,
+		MinBudget:     30000,
+		Embedding: &models.Embedding{
+			Model:   "local",
+			Hash:    "abc",
+			Vectors: [][]float32{{0.6, -0.8}, {1, 0}},
+		},
+	}
+
+	if err := s.CreateUserProfile(user); err != nil {
+		t.Fatalf("CreateUserProfile: %v", err)
+	}
+	if user.UpdatedAt.IsZero() {
+		t.Errorf("SaveUserProfile must set UpdatedAt")
+	}
+
+	got, err := s.GetUserProfileByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetUserProfileByID: %v", err)
+	}
+	if got.Name != user.Name || got.TelegramID != user.TelegramID || got.PromptVersion != user.PromptVersion {
+		t.Errorf("got %q/%d/%q, want %q/%d/%q", got.Name, got.TelegramID, got.PromptVersion,
+			user.Name, user.TelegramID, user.PromptVersion)
+	}
+	if got.Availability != user.Availability || got.MinBudget != user.MinBudget {
+		t.Errorf("signals = %v/%d, want %v/%d", got.Availability, got.MinBudget, user.Availability, user.MinBudget)
+	}
+	if !reflect.DeepEqual(got.Embedding, user.Embedding) {
+		t.Errorf("Embedding = %+v, want %+v", got.Embedding, user.Embedding)
+	}
+	if !reflect.DeepEqual(got.Skills, user.Skills) {
+		t.Errorf("Skills = %+v, want %+v", got.Skills, user.Skills)
+	}
+	if !reflect.DeepEqual(got.Experience, user.Experience) {
+		t.Errorf("Experience = %+v, want %+v", got.Experience, user.Experience)
+	}
+	if !reflect.DeepEqual(got.Interests, user.Interests) ||
+		!reflect.DeepEqual(got.SoftSkills, user.SoftSkills) ||
+		!reflect.DeepEqual(got.Goals, user.Goals) {
+		t.Errorf("lists = %v %v %v", got.Interests, got.SoftSkills, got.Goals)
+	}
+	if !reflect.DeepEqual(got.Verified, user.Verified) {
+		t.Errorf("Verified = %v, want %v", got.Verified, user.Verified)
+	}
+	if !got.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, created)
+	}
+
+	update := *got
+	update.Name = "Анна К."
+	update.Skills = map[string]models.SkillLevel{"Go": {Name: "Go", Level: 5}}
+	update.Experience = nil
+	if err := s.UpdateUserProfile(&update); err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	got = s.GetUserProfile(user.ID)
+	if got == nil || got.Name != "Анна К." {
+		t.Fatalf("GetUserProfile after update = %+v", got)
+	}
+	if len(got.Skills) != 1 || got.Skills["Go"].Level != 5 {
+		t.Errorf("Skills after update = %+v", got.Skills)
+	}
+	if len(got.Experience) != 0 {
+		t.Errorf("Experience after update = %+v", got.Experience)
+	}
+
+	found := false
+	for _, u := range s.ListUserProfiles() {
+		if u.ID == user.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListUserProfiles does not contain %s", user.ID)
+	}
+
+	if err := s.DeleteUserProfile(user.ID); err != nil {
+		t.Fatalf("DeleteUserProfile: %v", err)
+	}
+	if s.GetUserProfile(user.ID) != nil {
+		t.Errorf("profile still present after delete")
+	}
+}
+
+func testUserErrors(t *testing.T, s profile.Store) {
+	if s.GetUserProfile("storetest_missing") != nil {
+		t.Errorf("GetUserProfile(missing) != nil")
+	}
+	if _, err := s.GetUserProfileByID("storetest_missing"); !errors.Is(err, profile.ErrUserNotFound) {
+		t.Errorf("GetUserProfileByID(missing) = %v, want ErrUserNotFound", err)
+	}
+	if err := s.DeleteUserProfile("storetest_missing"); !errors.Is(err, profile.ErrUserNotFound) {
+		t.Errorf("DeleteUserProfile(missing) = %v, want ErrUserNotFound", err)
+	}
+	if err := s.CreateUserProfile(nil); !errors.Is(err, profile.ErrInvalidProfile) {
+		t.Errorf("CreateUserProfile(nil) = %v, want ErrInvalidProfile", err)
+	}
+	if err := s.UpdateUserProfile(&models.UserProfile{}); !errors.Is(err, profile.ErrInvalidProfile) {
+		t.Errorf("UpdateUserProfile(no id) = %v, want ErrInvalidProfile", err)
+	}
+}
+
+func testTaskRoundTrip(t *testing.T, s profile.Store) {
+	deadline := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	task := &models.TaskProfile{
+		ID:             "storetest_task",
+		Title:          "Парсер",
+		Description:    "Собрать данные",
+		RequiredSkills: map[string]int{"Python": 3, "SQL": 2},
+		Budget:         25000,
+		Deadline:       deadline,
+		CreatedBy:      "user_1",
+		Status:         "open",
+		CreatedAt:      deadline.AddDate(0, 0, -7),
+		PromptVersion:  "task/v2",
+		Embedding:      &models.Embedding{Model: "local", Hash: "def", Vectors: [][]float32{{0.25, 0.5}}},
+	}
+
+	if err := s.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	got, err := s.GetTaskByID(task.ID)
+	if err != nil {
+		t.Fatalf("GetTaskByID: %v", err)
+	}
+	if got.Title != task.Title || got.Description != task.Description ||
+		got.Budget != task.Budget || got.CreatedBy != task.CreatedBy || got.Status != task.Status ||
+		got.PromptVersion != task.PromptVersion {
+		t.Errorf("got %+v, want %+v", got, task)
+	}
+	if !reflect.DeepEqual(got.RequiredSkills, task.RequiredSkills) {
+		t.Errorf("RequiredSkills = %v, want %v", got.RequiredSkills, task.RequiredSkills)
+	}
+	if !reflect.DeepEqual(got.Embedding, task.Embedding) {
+		t.Errorf("Embedding = %+v, want %+v", got.Embedding, task.Embedding)
+	}
+	if !got.Deadline.Equal(deadline) || !got.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("times = %v/%v", got.Deadline, got.CreatedAt)
+	}
+
+	update := *got
+	update.Status = "assigned"
+	update.RequiredSkills = map[string]int{"Go": 1}
+	if err := s.UpdateTask(&update); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	got = s.GetTask(task.ID)
+	if got == nil || got.Status != "assigned" || !reflect.DeepEqual(got.RequiredSkills, map[string]int{"Go": 1}) {
+		t.Errorf("GetTask after update = %+v", got)
+	}
+
+	if !containsTask(s.ListTasks(), task.ID) {
+		t.Errorf("ListTasks does not contain %s", task.ID)
+	}
+
+	if err := s.DeleteTask(task.ID); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+	if s.GetTask(task.ID) != nil {
+		t.Errorf("task still present after delete")
+	}
+	if containsTask(s.ListTasks(), task.ID) {
+		t.Errorf("ListTasks still contains deleted task")
+	}
+}
+
+func testTaskErrors(t *testing.T, s profile.Store) {
+	if s.GetTask("storetest_missing") != nil {
+		t.Errorf("GetTask(missing) != nil")
+	}
+	if _, err := s.GetTaskByID("storetest_missing"); !errors.Is(err, profile.ErrTaskNotFound) {
+		t.Errorf("GetTaskByID(missing) = %v, want ErrTaskNotFound", err)
+	}
+	if err := s.DeleteTask("storetest_missing"); !errors.Is(err, profile.ErrTaskNotFound) {
+		t.Errorf("DeleteTask(missing) = %v, want ErrTaskNotFound", err)
+	}
+	if err := s.CreateTask(nil); !errors.Is(err, profile.ErrInvalidTask) {
+		t.Errorf("CreateTask(nil) = %v, want ErrInvalidTask", err)
+	}
+	if err := s.UpdateTask(&models.TaskProfile{}); !errors.Is(err, profile.ErrInvalidTask) {
+		t.Errorf("UpdateTask(no id) = %v, want ErrInvalidTask", err)
+	}
+}
+
+func testEmbeddings(t *testing.T, s profile.Store) {
+	user := &models.UserProfile{ID: "storetest_user", TelegramID: 7, Name: "Вера", Interests: []string{"боты"}}
+	task := &models.TaskProfile{ID: "storetest_task", Title: "Бот", Status: "open", CreatedAt: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)}
+	if err := s.CreateUserProfile(user); err != nil {
+		t.Fatalf("CreateUserProfile: %v", err)
+	}
+	if err := s.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	before := s.GetUserProfile(user.ID)
+
+	embedding := &models.Embedding{Model: "local", Hash: "abc", Vectors: [][]float32{{0.5, 0.25}}}
+	if err := s.SaveUserEmbedding(user.ID, embedding); err != nil {
+		t.Fatalf("SaveUserEmbedding: %v", err)
+	}
+	got := s.GetUserProfile(user.ID)
+	if got == nil || !reflect.DeepEqual(got.Embedding, embedding) {
+		t.Fatalf("GetUserProfile after SaveUserEmbedding = %+v", got)
+	}
+	if !got.UpdatedAt.Equal(before.UpdatedAt) || got.Name != user.Name || !reflect.DeepEqual(got.Interests, user.Interests) {
+		t.Errorf("SaveUserEmbedding changed other fields: %+v, was %+v", got, before)
+	}
+	if before.Embedding != nil {
+		t.Errorf("profile loaded before SaveUserEmbedding changed: %+v", before.Embedding)
+	}
+
+	if err := s.SaveTaskEmbedding(task.ID, embedding); err != nil {
+		t.Fatalf("SaveTaskEmbedding: %v", err)
+	}
+	if got := s.GetTask(task.ID); got == nil || !reflect.DeepEqual(got.Embedding, embedding) || got.Title != task.Title {
+		t.Errorf("GetTask after SaveTaskEmbedding = %+v", got)
+	}
+
+	if err := s.SaveUserEmbedding("missing", embedding); !errors.Is(err, profile.ErrUserNotFound) {
+		t.Errorf("SaveUserEmbedding(missing) = %v, want ErrUserNotFound", err)
+	}
+	if err := s.SaveTaskEmbedding("missing", embedding); !errors.Is(err, profile.ErrTaskNotFound) {
+		t.Errorf("SaveTaskEmbedding(missing) = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func testAvailableTasks(t *testing.T, s profile.Store) {
+	for i, status := range []string{"open", "assigned", "completed", "open"} {
+		task := &models.TaskProfile{
+			ID:        fmt.Sprintf("storetest_avail_%d", i),
+			Status:    status,
+			Deadline:  time.Now(),
+			CreatedAt: time.Now(),
+		}
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("SaveTask: %v", err)
+		}
+	}
+
+	var got []string
+	for _, task := range s.GetAvailableTasks() {
+		if task.Status != "open" {
+			t.Errorf("GetAvailableTasks returned %s with status %q", task.ID, task.Status)
+		}
+		got = append(got, task.ID)
+	}
+	for _, id := range []string{"storetest_avail_0", "storetest_avail_3"} {
+		if !contains(got, id) {
+			t.Errorf("GetAvailableTasks does not contain %s", id)
+		}
+	}
+}
+
+func testMatches(t *testing.T, s profile.Store) {
+	if err := s.SaveMatch(nil); !errors.Is(err, profile.ErrInvalidMatch) {
+		t.Errorf("SaveMatch(nil) = %v, want ErrInvalidMatch", err)
+	}
+
+	matches := []*models.MatchResult{
+		{TaskID: "t1", UserID: "u1", Score: 0.4, Reasons: []string{"a"}},
+		{TaskID: "t2", UserID: "u1", Score: 0.9, Reasons: []string{"b", "c"}},
+		{TaskID: "t1", UserID: "u2", Score: 0.7},
+	}
+	for _, m := range matches {
+		if err := s.SaveMatch(m); err != nil {
+			t.Fatalf("SaveMatch: %v", err)
+		}
+		if m.CreatedAt.IsZero() {
+			t.Errorf("SaveMatch must set CreatedAt")
+		}
+	}
+
+	// Повторное сохранение пары перезаписывает результат
+	if err := s.SaveMatch(&models.MatchResult{TaskID: "t1", UserID: "u1", Score: 0.5, Reasons: []string{"d"}}); err != nil {
+		t.Fatalf("SaveMatch: %v", err)
+	}
+
+	byUser := s.GetMatchesForUser("u1")
+	if len(byUser) != 2 || byUser[0].TaskID != "t2" || byUser[1].Score != 0.5 {
+		t.Errorf("GetMatchesForUser = %+v", byUser)
+	}
+	if len(byUser) == 2 && !reflect.DeepEqual(byUser[1].Reasons, []string{"d"}) {
+		t.Errorf("Reasons = %v", byUser[1].Reasons)
+	}
+
+	byTask := s.GetMatchesForTask("t1")
+	if len(byTask) != 2 || byTask[0].UserID != "u2" || byTask[1].UserID != "u1" {
+		t.Errorf("GetMatchesForTask = %+v", byTask)
+	}
+
+	if got := s.GetMatchesForUser("storetest_missing"); len(got) != 0 {
+		t.Errorf("GetMatchesForUser(missing) = %+v", got)
+	}
+}
+
+func testSessions(t *testing.T, s profile.Store) {
+	if s.GetSession(404) != nil {
+		t.Errorf("GetSession(missing) != nil")
+	}
+	if err := s.DeleteSession(404); !errors.Is(err, profile.ErrSessionNotFound) {
+		t.Errorf("DeleteSession(missing) = %v, want ErrSessionNotFound", err)
+	}
+	if err := s.SaveSession(nil); !errors.Is(err, profile.ErrInvalidSession) {
+		t.Errorf("SaveSession(nil) = %v, want ErrInvalidSession", err)
+	}
+
+	started := time.Date(2025, 7, 15, 20, 0, 0, 0, time.UTC)
+	session := &models.InterviewSession{
+		UserID:      7,
+		Type:        "profile",
+		CurrentStep: 3,
+		Answers:     map[string]interface{}{"q_0": "Анна", "q_1": "Go, Python", "q_2": "4"},
+		Context: map[string]interface{}{
+			"experience_level": "middle",
+			"mentioned_skills": []interface{}{"Go", "Python"},
+		},
+		StartedAt: started,
+	}
+	if err := s.SaveSession(session); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	if err := s.SaveSession(&models.InterviewSession{UserID: 8, Type: "task", StartedAt: started}); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+
+	got := s.GetSession(7)
+	if got == nil {
+		t.Fatalf("GetSession returned nil")
+	}
+	if got.Type != "profile" || got.CurrentStep != 3 || !got.StartedAt.Equal(started) {
+		t.Errorf("GetSession = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Answers, session.Answers) {
+		t.Errorf("Answers = %v, want %v", got.Answers, session.Answers)
+	}
+	if !reflect.DeepEqual(got.Context, session.Context) {
+		t.Errorf("Context = %v, want %v", got.Context, session.Context)
+	}
+
+	var ids []int
+	for _, sess := range s.ListSessions() {
+		ids = append(ids, int(sess.UserID))
+	}
+	sort.Ints(ids)
+	if !reflect.DeepEqual(ids, []int{7, 8}) {
+		t.Errorf("ListSessions user ids = %v", ids)
+	}
+
+	if err := s.DeleteSession(7); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	if s.GetSession(7) != nil {
+		t.Errorf("session still present after delete")
+	}
+}
+
+func testSeedDemoTasks(t *testing.T, s profile.Store) {
+	if tasks := s.ListTasks(); len(tasks) != 0 {
+		t.Fatalf("new store has %d tasks, want none", len(tasks))
+	}
+
+	if err := profile.SeedDemoTasks(s); err != nil {
+		t.Fatalf("SeedDemoTasks: %v", err)
+	}
+	seeded := len(s.ListTasks())
+	if seeded == 0 {
+		t.Fatal("SeedDemoTasks added no tasks")
+	}
+
+	// Повторный запуск (перезапуск бота с той же базой) не дублирует и не сбрасывает задачи
+	task := s.GetTask("task_1")
+	task.Status = "closed"
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("SaveTask: %v", err)
+	}
+	if err := profile.SeedDemoTasks(s); err != nil {
+		t.Fatalf("second SeedDemoTasks: %v", err)
+	}
+	if n := len(s.ListTasks()); n != seeded {
+		t.Errorf("tasks after second seed = %d, want %d", n, seeded)
+	}
+	if got := s.GetTask("task_1"); got == nil || got.Status != "closed" {
+		t.Errorf("second seed overwrote an existing task: %+v", got)
+	}
+}
+
+func containsTask(tasks []*models.TaskProfile, id string) bool {
+	for _, task := range tasks {
+		if task.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
+
+func testSkills(t *testing.T, s profile.Store) {
+	if err := s.SaveSkillAlias("", "Go"); !errors.Is(err, profile.ErrInvalidSkill) {
+		t.Errorf("SaveSkillAlias(empty) = %v, want ErrInvalidSkill", err)
+	}
+	if err := s.DeleteUnknownSkill("storetest_missing"); !errors.Is(err, profile.ErrSkillNotFound) {
+		t.Errorf("DeleteUnknownSkill(missing) = %v, want ErrSkillNotFound", err)
+	}
+
+	for _, a := range [][2]string{{"Vue3", "Vue"}, {"Airflow", "Airflow"}, {"Vue3", "Vue.js"}} {
+		if err := s.SaveSkillAlias(a[0], a[1]); err != nil {
+			t.Fatalf("SaveSkillAlias: %v", err)
+		}
+	}
+	// Перезаписанный синоним становится последним
+	var aliases []string
+	for _, a := range s.ListSkillAliases() {
+		aliases = append(aliases, a.Alias+"="+a.Skill)
+	}
+	if want := []string{"Airflow=Airflow", "Vue3=Vue.js"}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("ListSkillAliases = %v, want %v", aliases, want)
+	}
+
+	first := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+	seen := []struct {
+		key, name string
+		at        time.Time
+	}{
+		{"airflow", "Airflow", first},
+		{"dbt", "dbt", first},
+		{"airflow", "AirFlow", last},
+	}
+	for _, u := range seen {
+		if err := s.RecordUnknownSkill(u.key, u.name, u.at); err != nil {
+			t.Fatalf("RecordUnknownSkill: %v", err)
+		}
+	}
+
+	unknown := s.ListUnknownSkills()
+	if len(unknown) != 2 || unknown[0].Key != "airflow" || unknown[1].Key != "dbt" {
+		t.Fatalf("ListUnknownSkills = %+v", unknown)
+	}
+	if u := unknown[0]; u.Name != "Airflow" || u.Count != 2 || !u.FirstSeen.Equal(first) || !u.LastSeen.Equal(last) {
+		t.Errorf("unknown skill = %+v", u)
+	}
+
+	if err := s.DeleteUnknownSkill("airflow"); err != nil {
+		t.Fatalf("DeleteUnknownSkill: %v", err)
+	}
+	if unknown := s.ListUnknownSkills(); len(unknown) != 1 || unknown[0].Key != "dbt" {
+		t.Errorf("after delete: %+v", unknown)
+	}
+}
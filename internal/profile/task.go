@@ -1,13 +1,10 @@
 package profile
 
-import (
-	"errors"
-	"viget-mvp/internal/models"
-)
+import "viget-mvp/internal/models"
 
 func (s *InMemoryStorage) CreateTask(task *models.TaskProfile) error {
 	if task == nil || task.ID == "" {
-		return errors.New("invalid task")
+		return ErrInvalidTask
 	}
 	return s.SaveTask(task)
 }
@@ -15,14 +12,14 @@ func (s *InMemoryStorage) CreateTask(task *models.TaskProfile) error {
 func (s *InMemoryStorage) GetTaskByID(taskID string) (*models.TaskProfile, error) {
 	task := s.GetTask(taskID)
 	if task == nil {
-		return nil, errors.New("task not found")
+		return nil, ErrTaskNotFound
 	}
 	return task, nil
 }
 
 func (s *InMemoryStorage) UpdateTask(task *models.TaskProfile) error {
 	if task == nil || task.ID == "" {
-		return errors.New("invalid task")
+		return ErrInvalidTask
 	}
 	return s.SaveTask(task)
 }
@@ -31,7 +28,7 @@ func (s *InMemoryStorage) DeleteTask(taskID string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if _, ok := s.tasks[taskID]; !ok {
-		return errors.New("task not found")
+		return ErrTaskNotFound
 	}
 	delete(s.tasks, taskID)
 	return nil
@@ -47,3 +44,18 @@ func (s *InMemoryStorage) ListTasks() []*models.TaskProfile {
 	}
 	return tasks
 }
+
+// SaveTaskEmbedding — SaveUserEmbedding для задач
+func (s *InMemoryStorage) SaveTaskEmbedding(taskID string, embedding *models.Embedding) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	updated := *task
+	updated.Embedding = embedding
+	s.tasks[taskID] = &updated
+	return nil
+}
@@ -1,13 +1,10 @@
 package profile
 
-import (
-	"errors"
-	"viget-mvp/internal/models"
-)
+import "viget-mvp/internal/models"
 
 func (s *InMemoryStorage) CreateUserProfile(profile *models.UserProfile) error {
 	if profile == nil || profile.ID == "" {
-		return errors.New("invalid profile")
+		return ErrInvalidProfile
 	}
 	return s.SaveUserProfile(profile)
 }
@@ -15,14 +12,14 @@ func (s *InMemoryStorage) CreateUserProfile(profile *models.UserProfile) error {
 func (s *InMemoryStorage) GetUserProfileByID(userID string) (*models.UserProfile, error) {
 	profile := s.GetUserProfile(userID)
 	if profile == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 	return profile, nil
 }
 
 func (s *InMemoryStorage) UpdateUserProfile(profile *models.UserProfile) error {
 	if profile == nil || profile.ID == "" {
-		return errors.New("invalid profile")
+		return ErrInvalidProfile
 	}
 	return s.SaveUserProfile(profile)
 }
@@ -31,8 +28,35 @@ func (s *InMemoryStorage) DeleteUserProfile(userID string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if _, ok := s.users[userID]; !ok {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	delete(s.users, userID)
 	return nil
 }
+
+func (s *InMemoryStorage) ListUserProfiles() []*models.UserProfile {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	users := make([]*models.UserProfile, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	return users
+}
+
+// SaveUserEmbedding сохраняет копию профиля с новыми векторами: профиль,
+// полученный раньше через GetUserProfile, не меняется
+func (s *InMemoryStorage) SaveUserEmbedding(userID string, embedding *models.Embedding) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	updated := *user
+	updated.Embedding = embedding
+	s.users[userID] = &updated
+	return nil
+}
@@ -0,0 +1,218 @@
+// Package semantic сравнивает тексты по смыслу: интересы исполнителя и
+// описание задачи превращаются в векторы, близость — косинус угла между ними.
+package semantic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/pkg/gpt"
+)
+
+// Feature — функция для учета расхода модели
+const Feature = "embedding"
+
+// maxChunks ограничивает число фрагментов описания задачи
+const maxChunks = 16
+
+// Embedder превращает тексты в векторы. Реализации: LocalEmbedder и gpt.Client.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Revisioned — Embedder, векторы которого зависят от изменяемых данных:
+// у LocalEmbedder это таксономия навыков. Ревизия входит в имя модели
+// сохраненных векторов, поэтому с ее сменой они становятся устаревшими.
+type Revisioned interface {
+	Revision() string
+}
+
+// Index считает векторы профилей и задач и хранит их в самих профилях и задачах.
+// Векторы считаются заново, если сменилась модель или тексты.
+type Index struct {
+	embedder Embedder
+	model    string
+}
+
+// NewIndex создает индекс; model — имя, под которым сохраняются векторы embedder
+func NewIndex(embedder Embedder, model string) *Index {
+	return &Index{embedder: embedder, model: model}
+}
+
+// Model — имя, под которым сохраняются векторы: модель и ревизия Embedder
+func (x *Index) Model() string {
+	if r, ok := x.embedder.(Revisioned); ok {
+		if revision := r.Revision(); revision != "" {
+			return x.model + "@" + revision
+		}
+	}
+	return x.model
+}
+
+// ProfileTexts — тексты профиля для векторов: по одному на интерес
+func ProfileTexts(user *models.UserProfile) []string {
+	return user.Interests
+}
+
+// sentenceEnd делит описание на фрагменты: в длинном тексте одно упоминание
+// интереса почти не влияет на вектор, а во фрагменте — заметно
+var sentenceEnd = regexp.MustCompile(`[.!?;\n]+`)
+
+// TaskTexts — тексты задачи для векторов: название и фрагменты описания
+func TaskTexts(task *models.TaskProfile) []string {
+	var texts []string
+	if title := strings.TrimSpace(task.Title); title != "" {
+		texts = append(texts, title)
+	}
+	for _, chunk := range sentenceEnd.Split(task.Description, -1) {
+		if len(texts) >= maxChunks {
+			break
+		}
+		if chunk = strings.TrimSpace(chunk); chunk != "" {
+			texts = append(texts, chunk)
+		}
+	}
+	return texts
+}
+
+// Fresh сообщает, что векторы получены этой моделью из этих текстов
+func (x *Index) Fresh(e *models.Embedding, texts []string) bool {
+	if len(texts) == 0 {
+		return e == nil
+	}
+	return e != nil && e.Model == x.Model() && e.Hash == hashTexts(texts) && len(e.Vectors) == len(texts)
+}
+
+// UpdateProfiles возвращает профили с актуальными векторами. Устаревшие
+// профили копируются, исходные не меняются; updated — копии, которые нужно
+// сохранить. Все тексты отправляются одним запросом.
+func (x *Index) UpdateProfiles(ctx context.Context, users []*models.UserProfile) (result, updated []*models.UserProfile, err error) {
+	var stale []int
+	var batch [][]string
+	for i, user := range users {
+		if texts := ProfileTexts(user); !x.Fresh(user.Embedding, texts) {
+			stale = append(stale, i)
+			batch = append(batch, texts)
+		}
+	}
+
+	embeddings, err := x.embedAll(ctx, batch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result = append([]*models.UserProfile(nil), users...)
+	for n, i := range stale {
+		user := *users[i]
+		user.Embedding = embeddings[n]
+		result[i] = &user
+		updated = append(updated, &user)
+	}
+	return result, updated, nil
+}
+
+// UpdateTasks — UpdateProfiles для задач
+func (x *Index) UpdateTasks(ctx context.Context, tasks []*models.TaskProfile) (result, updated []*models.TaskProfile, err error) {
+	var stale []int
+	var batch [][]string
+	for i, task := range tasks {
+		if texts := TaskTexts(task); !x.Fresh(task.Embedding, texts) {
+			stale = append(stale, i)
+			batch = append(batch, texts)
+		}
+	}
+
+	embeddings, err := x.embedAll(ctx, batch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result = append([]*models.TaskProfile(nil), tasks...)
+	for n, i := range stale {
+		task := *tasks[i]
+		task.Embedding = embeddings[n]
+		result[i] = &task
+		updated = append(updated, &task)
+	}
+	return result, updated, nil
+}
+
+// EmbedProfile заполняет векторы профиля, если они устарели
+func (x *Index) EmbedProfile(ctx context.Context, user *models.UserProfile) error {
+	result, updated, err := x.UpdateProfiles(ctx, []*models.UserProfile{user})
+	if err == nil && len(updated) > 0 {
+		user.Embedding = result[0].Embedding
+	}
+	return err
+}
+
+// EmbedTask заполняет векторы задачи, если они устарели
+func (x *Index) EmbedTask(ctx context.Context, task *models.TaskProfile) error {
+	result, updated, err := x.UpdateTasks(ctx, []*models.TaskProfile{task})
+	if err == nil && len(updated) > 0 {
+		task.Embedding = result[0].Embedding
+	}
+	return err
+}
+
+// embedAll считает векторы нескольких наборов текстов одним запросом;
+// для пустого набора возвращает nil
+func (x *Index) embedAll(ctx context.Context, batch [][]string) ([]*models.Embedding, error) {
+	var texts []string
+	for _, b := range batch {
+		texts = append(texts, b...)
+	}
+	// Ревизию берем до расчета: если она сменится по ходу, векторы
+	// окажутся под старой и будут пересчитаны
+	model := x.Model()
+
+	var vectors [][]float32
+	if len(texts) > 0 {
+		var err error
+		vectors, err = x.embedder.Embed(gpt.WithFeature(ctx, Feature), texts)
+		if err != nil {
+			return nil, fmt.Errorf("embed: %w", err)
+		}
+		if len(vectors) != len(texts) {
+			return nil, fmt.Errorf("embed: got %d vectors for %d texts", len(vectors), len(texts))
+		}
+	}
+
+	embeddings := make([]*models.Embedding, len(batch))
+	for i, b := range batch {
+		if len(b) == 0 {
+			continue
+		}
+		embeddings[i] = &models.Embedding{Model: model, Hash: hashTexts(b), Vectors: vectors[:len(b)]}
+		vectors = vectors[len(b):]
+	}
+	return embeddings, nil
+}
+
+func hashTexts(texts []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(texts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Cosine — косинус угла между векторами от -1 до 1; 0, если длины разные или вектор нулевой
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,135 @@
+package semantic
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"viget-mvp/internal/skills"
+)
+
+// LocalModel — имя модели, под которым сохраняются векторы LocalEmbedder;
+// к нему добавляется ревизия таксономии (Revision)
+const LocalModel = "local-v1"
+
+// LocalDimensions — размерность векторов LocalEmbedder
+const LocalDimensions = 256
+
+// Веса признаков LocalEmbedder
+const (
+	wordWeight    = 1.0
+	trigramWeight = 0.3 // триграммы сглаживают словоформы: "разработка" ~ "разработчик"
+	conceptWeight = 2.0 // навык из таксономии: "машинное обучение" ~ "ML"
+)
+
+// stopWords не несут смысла для сравнения
+var stopWords = map[string]bool{
+	"и": true, "в": true, "во": true, "на": true, "с": true, "со": true, "по": true,
+	"для": true, "из": true, "к": true, "о": true, "об": true, "от": true, "до": true,
+	"а": true, "но": true, "или": true, "не": true, "что": true, "как": true, "это": true,
+	"the": true, "a": true, "an": true, "and": true, "or": true, "of": true, "to": true,
+	"for": true, "in": true, "on": true, "with": true,
+}
+
+// LocalEmbedder считает векторы без обращения к модели: признаки (слова,
+// их триграммы и найденные в тексте навыки таксономии) раскладываются по
+// координатам хешированием. Результат детерминирован, поэтому подходит для
+// тестов и работы без API; смысл он улавливает только через таксономию.
+type LocalEmbedder struct {
+	taxonomy *skills.Taxonomy
+}
+
+// NewLocalEmbedder создает локальный способ; taxonomy может быть nil
+func NewLocalEmbedder(taxonomy *skills.Taxonomy) *LocalEmbedder {
+	return &LocalEmbedder{taxonomy: taxonomy}
+}
+
+// Revision — ревизия таксономии: навыки из нее входят в векторы, поэтому
+// после правок администратора векторы нужно пересчитать
+func (e *LocalEmbedder) Revision() string {
+	if e.taxonomy == nil {
+		return ""
+	}
+	return e.taxonomy.Revision()
+}
+
+func (e *LocalEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vector(text)
+	}
+	return vectors, nil
+}
+
+func (e *LocalEmbedder) vector(text string) []float32 {
+	v := make([]float64, LocalDimensions)
+	words := tokenize(text)
+
+	for _, w := range words {
+		if stopWords[w] {
+			continue
+		}
+		addFeature(v, "w:"+stem(w), wordWeight)
+		runes := []rune("<" + w + ">")
+		for i := 0; i+3 <= len(runes); i++ {
+			addFeature(v, "t:"+string(runes[i:i+3]), trigramWeight)
+		}
+	}
+
+	// Навыки ищем по фразам до трех слов: "машинное обучение", "react native"
+	if e.taxonomy != nil {
+		for n := 3; n >= 1; n-- {
+			for i := 0; i+n <= len(words); i++ {
+				if skill, ok := e.taxonomy.Canonical(strings.Join(words[i:i+n], " ")); ok {
+					addFeature(v, "c:"+skill, conceptWeight)
+				}
+			}
+		}
+	}
+
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	result := make([]float32, LocalDimensions)
+	if norm == 0 {
+		return result
+	}
+	norm = math.Sqrt(norm)
+	for i, x := range v {
+		result[i] = float32(x / norm)
+	}
+	return result
+}
+
+// addFeature добавляет признак в координату по хешу; знак тоже от хеша,
+// чтобы коллизии разных признаков в среднем гасили друг друга
+func addFeature(v []float64, feature string, weight float64) {
+	h := fnv.New32a()
+	h.Write([]byte(feature))
+	sum := h.Sum32()
+	if sum&(1<<31) != 0 {
+		weight = -weight
+	}
+	v[sum%LocalDimensions] += weight
+}
+
+// tokenize разбивает текст на слова в нижнем регистре; "ML-модель" — два слова
+func tokenize(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "ё", "е")
+	return strings.FieldsFunc(text, func(r rune) bool {
+		// Символы из названий навыков вроде C++ и C# остаются частью слова
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+}
+
+// stem грубо отбрасывает окончание: первые пять букв длинного слова
+func stem(word string) string {
+	runes := []rune(word)
+	if len(runes) > 5 {
+		return string(runes[:5])
+	}
+	return word
+}
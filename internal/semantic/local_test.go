@@ -0,0 +1,38 @@
+package semantic
+
+import (
+	"context"
+	"testing"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/skills"
+)
+
+func TestLocalEmbeddingsStaleAfterTaxonomyEdit(t *testing.T) {
+	taxonomy := skills.NewTaxonomy(skills.Builtin)
+	index := NewIndex(NewLocalEmbedder(taxonomy), LocalModel)
+
+	user := &models.UserProfile{ID: "1", Interests: []string{"нейросетки"}}
+	if err := index.EmbedProfile(context.Background(), user); err != nil {
+		t.Fatalf("EmbedProfile: %v", err)
+	}
+	if !index.Fresh(user.Embedding, ProfileTexts(user)) {
+		t.Fatal("embedding is stale right after it was computed")
+	}
+
+	if _, err := taxonomy.Merge("нейросетки", "Machine Learning"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if index.Fresh(user.Embedding, ProfileTexts(user)) {
+		t.Error("embedding is still fresh after the taxonomy changed")
+	}
+
+	// Та же правка еще раз ничего не меняет — ревизия прежняя
+	revision := taxonomy.Revision()
+	if _, err := taxonomy.Merge("нейросетки", "Machine Learning"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if taxonomy.Revision() != revision {
+		t.Error("revision changed after a no-op merge")
+	}
+}
@@ -0,0 +1,101 @@
+package semantic
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+)
+
+const (
+	// refreshBatch — сколько профилей или задач отправляется в одном запросе
+	refreshBatch = 64
+	// refreshTimeout ограничивает расчет векторов одной пачки
+	refreshTimeout = time.Minute
+)
+
+// Refresh пересчитывает устаревшие векторы профилей и задач из store и
+// сохраняет их. Подбор читает только сохраненные векторы, поэтому записи,
+// сохраненные до появления векторов или другой моделью, обновляются здесь,
+// а не во время запроса пользователя. Возвращает число обновленных записей.
+func (x *Index) Refresh(ctx context.Context, store profile.Store) (int, error) {
+	var refreshed int
+
+	users := store.ListUserProfiles()
+	for start := 0; start < len(users); start += refreshBatch {
+		batch := users[start:min(start+refreshBatch, len(users))]
+		batchCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		_, updated, err := x.UpdateProfiles(batchCtx, batch)
+		cancel()
+		if err != nil {
+			return refreshed, err
+		}
+		for _, user := range updated {
+			// Профиль могли изменить, пока считались векторы: сохраняем
+			// векторы, только если тексты текущей версии те же. Профиль из
+			// store не меняем — его читают другие горутины
+			current := store.GetUserProfile(user.ID)
+			if current == nil || !sameTexts(user.Embedding, ProfileTexts(current)) {
+				continue
+			}
+			if err := store.SaveUserEmbedding(user.ID, user.Embedding); err != nil {
+				return refreshed, err
+			}
+			refreshed++
+		}
+	}
+
+	tasks := store.ListTasks()
+	for start := 0; start < len(tasks); start += refreshBatch {
+		batch := tasks[start:min(start+refreshBatch, len(tasks))]
+		batchCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		_, updated, err := x.UpdateTasks(batchCtx, batch)
+		cancel()
+		if err != nil {
+			return refreshed, err
+		}
+		for _, task := range updated {
+			current := store.GetTask(task.ID)
+			if current == nil || !sameTexts(task.Embedding, TaskTexts(current)) {
+				continue
+			}
+			if err := store.SaveTaskEmbedding(task.ID, task.Embedding); err != nil {
+				return refreshed, err
+			}
+			refreshed++
+		}
+	}
+
+	return refreshed, nil
+}
+
+// StartRefresher вызывает Refresh сразу и затем каждые interval в фоне
+func (x *Index) StartRefresher(store profile.Store, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if n, err := x.Refresh(context.Background(), store); err != nil {
+				log.Printf("semantic: refresh embeddings: %v", err)
+			} else if n > 0 {
+				log.Printf("semantic: refreshed embeddings of %d profiles and tasks", n)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// sameTexts сообщает, что векторы e посчитаны по texts (для пустого набора векторов нет)
+func sameTexts(e *models.Embedding, texts []string) bool {
+	if e == nil {
+		return len(texts) == 0
+	}
+	return e.Hash == hashTexts(texts)
+}
@@ -0,0 +1,48 @@
+package semantic
+
+import (
+	"context"
+	"testing"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+)
+
+func TestRefreshSavesStaleEmbeddings(t *testing.T) {
+	store := profile.NewInMemoryStorage()
+	user := &models.UserProfile{ID: "1", TelegramID: 1, Name: "Анна", Interests: []string{"веб-разработка"}}
+	if err := store.SaveUserProfile(user); err != nil {
+		t.Fatalf("SaveUserProfile: %v", err)
+	}
+	if err := profile.SeedDemoTasks(store); err != nil {
+		t.Fatalf("SeedDemoTasks: %v", err)
+	}
+
+	updatedAt := store.GetUserProfile("1").UpdatedAt
+
+	index := NewIndex(NewLocalEmbedder(nil), LocalModel)
+	if n, err := index.Refresh(context.Background(), store); err != nil || n == 0 {
+		t.Fatalf("Refresh = %d, %v; want stale records updated", n, err)
+	}
+
+	saved := store.GetUserProfile("1")
+	if !index.Fresh(saved.Embedding, ProfileTexts(saved)) {
+		t.Errorf("profile embedding was not refreshed: %+v", saved.Embedding)
+	}
+	// Пересчет векторов — не правка профиля: дата обновления прежняя
+	if !saved.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Refresh changed UpdatedAt: %v, was %v", saved.UpdatedAt, updatedAt)
+	}
+	if user.Embedding != nil {
+		t.Errorf("Refresh changed the profile held by the caller: %+v", user.Embedding)
+	}
+	for _, task := range store.ListTasks() {
+		if !index.Fresh(task.Embedding, TaskTexts(task)) {
+			t.Errorf("task %s embedding was not refreshed", task.ID)
+		}
+	}
+
+	if n, err := index.Refresh(context.Background(), store); err != nil || n != 0 {
+		t.Errorf("second Refresh = %d, %v; want nothing to update", n, err)
+	}
+}
@@ -0,0 +1,65 @@
+package skills
+
+// Builtin — навыки, которые чаще всего встречаются в профилях и задачах.
+// Остальные администратор добавляет по мере появления (/skills).
+var Builtin = []Skill{
+	// Языки
+	{Name: "JavaScript", Aliases: []string{"JS", "ECMAScript", "ES6", "джаваскрипт", "яваскрипт", "жс"}},
+	{Name: "TypeScript", Aliases: []string{"TS", "тайпскрипт"}},
+	{Name: "Python", Aliases: []string{"Python3", "py", "питон", "пайтон"}},
+	{Name: "Go", Aliases: []string{"Golang", "го", "голанг"}},
+	{Name: "Java", Aliases: []string{"джава", "ява"}},
+	{Name: "Kotlin", Aliases: []string{"котлин"}},
+	{Name: "Swift", Aliases: []string{"свифт"}},
+	{Name: "C#", Aliases: []string{"CSharp", "C sharp", "си шарп", "шарп"}},
+	{Name: "C++", Aliases: []string{"cpp", "плюсы", "си плюс плюс"}},
+	{Name: "PHP", Aliases: []string{"пхп"}},
+	{Name: "Ruby", Aliases: []string{"руби"}},
+	{Name: "Rust", Aliases: []string{"раст"}},
+	{Name: "Dart", Aliases: []string{"дарт"}},
+	{Name: "SQL", Aliases: []string{"эскуэль", "скуль"}},
+	{Name: "HTML", Aliases: []string{"HTML5", "хтмл"}},
+	{Name: "CSS", Aliases: []string{"CSS3", "цсс"}},
+	{Name: "1С", Aliases: []string{"1C", "1С:Предприятие", "один эс"}},
+
+	// Фреймворки и платформы
+	{Name: "React", Aliases: []string{"ReactJS", "React.js", "реакт"}},
+	{Name: "React Native", Aliases: []string{"RN", "реакт нейтив"}},
+	{Name: "Vue", Aliases: []string{"Vue.js", "VueJS", "Vue3", "вью"}},
+	{Name: "Angular", Aliases: []string{"AngularJS", "ангуляр"}},
+	{Name: "Node.js", Aliases: []string{"Node", "NodeJS", "нода", "нод"}},
+	{Name: "Django", Aliases: []string{"джанго"}},
+	{Name: "FastAPI", Aliases: []string{"фастапи"}},
+	{Name: "Flask", Aliases: []string{"фласк"}},
+	{Name: "Laravel", Aliases: []string{"ларавель"}},
+	{Name: "Spring", Aliases: []string{"Spring Boot", "спринг"}},
+	{Name: ".NET", Aliases: []string{"dotnet", "дотнет"}},
+	{Name: "Flutter", Aliases: []string{"флаттер"}},
+	{Name: "Android", Aliases: []string{"андроид"}},
+	{Name: "iOS", Aliases: []string{"айос"}},
+	{Name: "BeautifulSoup", Aliases: []string{"bs4", "Beautiful Soup"}},
+	{Name: "Firebase", Aliases: []string{"файрбейс"}},
+
+	// Данные и инфраструктура
+	{Name: "PostgreSQL", Aliases: []string{"Postgres", "psql", "pg", "постгрес", "постгрескл"}},
+	{Name: "MySQL", Aliases: []string{"мускул", "майскл"}},
+	{Name: "MongoDB", Aliases: []string{"Mongo", "монго"}},
+	{Name: "Redis", Aliases: []string{"редис"}},
+	{Name: "Docker", Aliases: []string{"докер"}},
+	{Name: "Kubernetes", Aliases: []string{"k8s", "кубернетес", "кубер"}},
+	{Name: "Linux", Aliases: []string{"линукс"}},
+	{Name: "Git", Aliases: []string{"гит"}},
+	{Name: "Machine Learning", Aliases: []string{"ML", "машинное обучение"}},
+	{Name: "Data Analysis", Aliases: []string{"анализ данных", "аналитика данных", "Data Analytics"}},
+	{Name: "Excel", Aliases: []string{"MS Excel", "эксель"}},
+	{Name: "Testing", Aliases: []string{"QA", "тестирование"}},
+
+	// Дизайн и маркетинг
+	{Name: "Figma", Aliases: []string{"фигма"}},
+	{Name: "Photoshop", Aliases: []string{"PS", "фотошоп"}},
+	{Name: "UI/UX", Aliases: []string{"UX/UI", "UX", "UI", "дизайн интерфейсов", "UI/UX дизайн"}},
+	{Name: "Copywriting", Aliases: []string{"копирайтинг"}},
+	{Name: "SMM", Aliases: []string{"смм"}},
+	{Name: "SEO", Aliases: []string{"сео"}},
+	{Name: "Project Management", Aliases: []string{"управление проектами", "PM"}},
+}
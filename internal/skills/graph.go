@@ -0,0 +1,141 @@
+package skills
+
+import "sort"
+
+// EdgeKind — вид связи между навыками
+type EdgeKind int
+
+const (
+	// EdgeParent — A включает B, как JavaScript включает React. Знающему B
+	// засчитывается A с весом Weight, знающему A засчитывается B с весом Weight/2:
+	// React-разработчик знает JavaScript, а JavaScript-разработчику React еще учить.
+	EdgeParent EdgeKind = iota
+	// EdgeSimilar — навыки взаимозаменяемы в обе стороны с весом Weight, как Vue и React
+	EdgeSimilar
+)
+
+// Edge — связь навыков A и B; Weight от 0 до 1 — какая доля уровня засчитывается
+type Edge struct {
+	A, B   string
+	Kind   EdgeKind
+	Weight float64
+}
+
+// Parent — связь "parent включает child"
+func Parent(parent, child string, weight float64) Edge {
+	return Edge{A: parent, B: child, Kind: EdgeParent, Weight: weight}
+}
+
+// Similar — связь похожих навыков
+func Similar(a, b string, weight float64) Edge {
+	return Edge{A: a, B: b, Kind: EdgeSimilar, Weight: weight}
+}
+
+// Related — навык, который частично заменяет требуемый
+type Related struct {
+	Skill  string
+	Weight float64
+}
+
+// Graph хранит связи навыков по каноническим именам. Связи не транзитивны:
+// засчитываются только навыки, связанные с требуемым напрямую.
+type Graph struct {
+	// related — ключ требуемого навыка → навыки, которые его частично заменяют
+	related map[string][]Related
+}
+
+func NewGraph(edges []Edge) *Graph {
+	g := &Graph{related: make(map[string][]Related)}
+	for _, e := range edges {
+		switch e.Kind {
+		case EdgeParent:
+			g.add(e.B, e.A, e.Weight)
+			g.add(e.A, e.B, e.Weight/2)
+		case EdgeSimilar:
+			g.add(e.A, e.B, e.Weight)
+			g.add(e.B, e.A, e.Weight)
+		}
+	}
+	for key := range g.related {
+		related := g.related[key]
+		sort.Slice(related, func(i, j int) bool {
+			if related[i].Weight != related[j].Weight {
+				return related[i].Weight > related[j].Weight
+			}
+			return related[i].Skill < related[j].Skill
+		})
+	}
+	return g
+}
+
+// add засчитывает знание have для требования need; из нескольких связей остается самая сильная
+func (g *Graph) add(have, need string, weight float64) {
+	key := Key(need)
+	for i, r := range g.related[key] {
+		if r.Skill == have {
+			if weight > r.Weight {
+				g.related[key][i].Weight = weight
+			}
+			return
+		}
+	}
+	g.related[key] = append(g.related[key], Related{Skill: have, Weight: weight})
+}
+
+// Related возвращает навыки, частично заменяющие skill, — самые близкие первыми
+func (g *Graph) Related(skill string) []Related {
+	if g == nil {
+		return nil
+	}
+	return g.related[Key(skill)]
+}
+
+// BuiltinEdges — связи навыков из Builtin
+var BuiltinEdges = []Edge{
+	// Веб
+	Parent("JavaScript", "React", 0.8),
+	Parent("JavaScript", "Vue", 0.8),
+	Parent("JavaScript", "Angular", 0.8),
+	Parent("JavaScript", "Node.js", 0.8),
+	Parent("React", "React Native", 0.8),
+	Similar("JavaScript", "TypeScript", 0.8),
+	Similar("Vue", "React", 0.6),
+	Similar("Angular", "React", 0.5),
+	Similar("Angular", "Vue", 0.5),
+	Similar("HTML", "CSS", 0.6),
+
+	// Бэкенд
+	Parent("Python", "Django", 0.8),
+	Parent("Python", "FastAPI", 0.8),
+	Parent("Python", "Flask", 0.8),
+	Parent("Python", "BeautifulSoup", 0.8),
+	Parent("PHP", "Laravel", 0.8),
+	Parent("Java", "Spring", 0.8),
+	Parent("C#", ".NET", 0.8),
+	Similar("Django", "Flask", 0.6),
+	Similar("FastAPI", "Flask", 0.7),
+	Similar("Django", "FastAPI", 0.6),
+	Similar("Java", "Kotlin", 0.6),
+	Similar("Java", "C#", 0.5),
+
+	// Мобильная разработка
+	Parent("Dart", "Flutter", 0.8),
+	Parent("Swift", "iOS", 0.7),
+	Parent("Kotlin", "Android", 0.7),
+	Similar("Flutter", "React Native", 0.4),
+
+	// Данные и инфраструктура
+	Parent("SQL", "PostgreSQL", 0.8),
+	Parent("SQL", "MySQL", 0.8),
+	Similar("PostgreSQL", "MySQL", 0.7),
+	Similar("Python", "Data Analysis", 0.4),
+	Similar("Python", "Machine Learning", 0.4),
+	Similar("Docker", "Kubernetes", 0.5),
+	Similar("Docker", "Linux", 0.3),
+
+	// Дизайн и маркетинг
+	Similar("Figma", "UI/UX", 0.6),
+	Similar("Figma", "Photoshop", 0.4),
+	Similar("Copywriting", "SEO", 0.4),
+	Similar("Copywriting", "SMM", 0.4),
+}
@@ -0,0 +1,291 @@
+// Package skills приводит названия навыков к каноническим: "JS", "js" и
+// "джаваскрипт" — это "JavaScript". Каноническое имя навыка — его
+// идентификатор: под ним навык хранится в профилях и задачах.
+package skills
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+)
+
+// Skill — канонический навык и его синонимы на русском и английском
+type Skill struct {
+	Name    string
+	Aliases []string
+}
+
+// Taxonomy сопоставляет написания навыков каноническим именам. Незнакомые
+// навыки, встреченные при извлечении, копятся в хранилище, пока администратор
+// не добавит их как новые (Add) или не объявит синонимами известных (Merge).
+// Методы можно вызывать из разных горутин.
+type Taxonomy struct {
+	mutex sync.RWMutex
+	// names — ключ написания (Key) → каноническое имя
+	names map[string]string
+	// store — где хранятся правки администратора (nil — только в памяти)
+	store profile.SkillStore
+	// revision — хеш names, меняется с каждой правкой
+	revision string
+}
+
+// NewTaxonomy создает таксономию из списка навыков, например Builtin
+func NewTaxonomy(skills []Skill) *Taxonomy {
+	t := &Taxonomy{names: make(map[string]string)}
+	for _, skill := range skills {
+		t.names[Key(skill.Name)] = skill.Name
+		for _, alias := range skill.Aliases {
+			t.names[Key(alias)] = skill.Name
+		}
+	}
+	t.revise()
+	return t
+}
+
+// SetStore применяет сохраненные правки администратора и сохраняет в store
+// новые. Правки, сделанные до вызова, остаются только в памяти.
+func (t *Taxonomy) SetStore(store profile.SkillStore) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.store = store
+	for _, a := range store.ListSkillAliases() {
+		t.merge(a.Alias, a.Skill)
+	}
+	t.revise()
+}
+
+// Revision — отпечаток сопоставлений: одинаков у одинаковых таксономий и
+// меняется после Merge и Add, которые что-то изменили
+func (t *Taxonomy) Revision() string {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.revision
+}
+
+// revise пересчитывает revision; вызывается под mutex
+func (t *Taxonomy) revise() {
+	keys := make([]string, 0, len(t.names))
+	for key := range t.names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", key, t.names[key])
+	}
+	t.revision = hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// Key — написание навыка без регистра, пробелов и разделителей:
+// "React.js", "react js" и "ReactJS" дают один ключ
+func Key(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == 'ё':
+			b.WriteRune('е')
+		case unicode.IsSpace(r), r == '.', r == '-', r == '_', r == '/':
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Canonical возвращает каноническое имя навыка; для незнакомого — само
+// название без лишних пробелов и known == false
+func (t *Taxonomy) Canonical(name string) (canonical string, known bool) {
+	name = strings.TrimSpace(name)
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if canonical, ok := t.names[Key(name)]; ok {
+		return canonical, true
+	}
+	return name, false
+}
+
+// Resolve — Canonical, которая к тому же отмечает незнакомый навык для администратора
+func (t *Taxonomy) Resolve(name string) string {
+	canonical, known := t.Canonical(name)
+	if known || canonical == "" {
+		return canonical
+	}
+
+	t.mutex.RLock()
+	store := t.store
+	t.mutex.RUnlock()
+
+	if store != nil {
+		if err := store.RecordUnknownSkill(Key(canonical), canonical, time.Now()); err != nil {
+			log.Printf("skills: record unknown skill %q: %v", canonical, err)
+		}
+	}
+	return canonical
+}
+
+// Merge объявляет alias синонимом навыка skill. Если skill незнаком, он
+// становится новым каноническим навыком; если alias сам был каноническим,
+// его синонимы переходят к skill. Возвращает каноническое имя skill.
+func (t *Taxonomy) Merge(alias, skill string) (string, error) {
+	alias, skill = strings.TrimSpace(alias), strings.TrimSpace(skill)
+	if Key(alias) == "" || Key(skill) == "" {
+		return "", fmt.Errorf("empty skill name")
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	target := t.merge(alias, skill)
+	t.revise()
+	if t.store == nil {
+		return target, nil
+	}
+
+	if err := t.store.SaveSkillAlias(alias, target); err != nil {
+		return "", fmt.Errorf("save alias %q: %w", alias, err)
+	}
+	for _, key := range []string{Key(alias), Key(target)} {
+		if err := t.store.DeleteUnknownSkill(key); err != nil && !errors.Is(err, profile.ErrSkillNotFound) {
+			return "", fmt.Errorf("forget unknown skill %q: %w", key, err)
+		}
+	}
+	return target, nil
+}
+
+// Add делает незнакомый навык каноническим под именем name
+func (t *Taxonomy) Add(name string) (string, error) {
+	return t.Merge(name, name)
+}
+
+// merge вызывается под mutex
+func (t *Taxonomy) merge(alias, skill string) string {
+	target, ok := t.names[Key(skill)]
+	if !ok {
+		target = skill
+		t.names[Key(skill)] = skill
+	}
+
+	// Alias был каноническим навыком — переносим к target и его синонимы.
+	// Синоним другого навыка переходит к target один.
+	if previous, ok := t.names[Key(alias)]; ok && previous != target && Key(previous) == Key(alias) {
+		for key, name := range t.names {
+			if name == previous {
+				t.names[key] = target
+			}
+		}
+	}
+	t.names[Key(alias)] = target
+	return target
+}
+
+// Unknown возвращает незнакомые навыки, самые частые первыми
+func (t *Taxonomy) Unknown() []models.UnknownSkill {
+	t.mutex.RLock()
+	store := t.store
+	t.mutex.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+	return store.ListUnknownSkills()
+}
+
+// NormalizeProfile приводит навыки профиля к каноническим, отмечая незнакомые
+func (t *Taxonomy) NormalizeProfile(user *models.UserProfile) {
+	skills := make(map[string]models.SkillLevel, len(user.Skills))
+	for name, skill := range user.Skills {
+		name = t.Resolve(name)
+		skill.Name = name
+		skills[name] = strongerSkill(skills[name], skill)
+	}
+	user.Skills = skills
+	user.Verified = t.CanonicalSet(user.Verified)
+
+	for i := range user.Experience {
+		user.Experience[i].Skills = t.resolveList(user.Experience[i].Skills)
+	}
+}
+
+// NormalizeTask приводит требования задачи к каноническим навыкам, отмечая незнакомые
+func (t *Taxonomy) NormalizeTask(task *models.TaskProfile) {
+	levels := make(map[string]int, len(task.RequiredSkills))
+	for name, level := range task.RequiredSkills {
+		name = t.Resolve(name)
+		if level > levels[name] {
+			levels[name] = level
+		}
+	}
+	task.RequiredSkills = levels
+}
+
+// CanonicalSkills возвращает копию навыков с каноническими именами. Если
+// навык записан несколькими написаниями, остается более сильная запись.
+func (t *Taxonomy) CanonicalSkills(skills map[string]models.SkillLevel) map[string]models.SkillLevel {
+	result := make(map[string]models.SkillLevel, len(skills))
+	for name, skill := range skills {
+		name, _ = t.Canonical(name)
+		skill.Name = name
+		result[name] = strongerSkill(result[name], skill)
+	}
+	return result
+}
+
+// CanonicalLevels — CanonicalSkills для требований задачи: навык → минимальный уровень
+func (t *Taxonomy) CanonicalLevels(levels map[string]int) map[string]int {
+	result := make(map[string]int, len(levels))
+	for name, level := range levels {
+		name, _ = t.Canonical(name)
+		if level > result[name] {
+			result[name] = level
+		}
+	}
+	return result
+}
+
+// CanonicalSet — CanonicalSkills для множества навыков (например, подтвержденных)
+func (t *Taxonomy) CanonicalSet(set map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(set))
+	for name, ok := range set {
+		name, _ = t.Canonical(name)
+		result[name] = result[name] || ok
+	}
+	return result
+}
+
+func (t *Taxonomy) resolveList(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var result []string
+	for _, name := range names {
+		name = t.Resolve(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// strongerSkill выбирает из двух записей одного навыка более высокий уровень;
+// подтверждение сохраняется, если подтверждена любая из них
+func strongerSkill(a, b models.SkillLevel) models.SkillLevel {
+	verified := a.Verified || b.Verified
+	if b.Level > a.Level || (b.Level == a.Level && b.Confidence > a.Confidence) {
+		a = b
+	}
+	a.Verified = verified
+	return a
+}
@@ -0,0 +1,159 @@
+package skills
+
+import (
+	"testing"
+
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/profile"
+)
+
+func TestKeyFoldsSpelling(t *testing.T) {
+	for _, names := range [][]string{
+		{"React.js", "react js", "ReactJS", "REACT-JS", "react_js", "react/js"},
+		{"Ёжик", "ежик", " Е ж и к "},
+	} {
+		for _, name := range names[1:] {
+			if Key(name) != Key(names[0]) {
+				t.Errorf("Key(%q) = %q, want %q as for %q", name, Key(name), Key(names[0]), names[0])
+			}
+		}
+	}
+	if Key("C#") == Key("C") || Key("C++") == Key("C") {
+		t.Error("Key drops characters that tell skills apart")
+	}
+}
+
+func TestCanonicalResolvesSynonyms(t *testing.T) {
+	taxonomy := NewTaxonomy(Builtin)
+	tests := map[string]string{
+		"js":          "JavaScript",
+		"  JS  ":      "JavaScript",
+		"джаваскрипт": "JavaScript",
+		"golang":      "Go",
+		"Голанг":      "Go",
+		"react.js":    "React",
+		"Node JS":     "Node.js",
+	}
+	for name, want := range tests {
+		if got, known := taxonomy.Canonical(name); got != want || !known {
+			t.Errorf("Canonical(%q) = %q, %v; want %q", name, got, known, want)
+		}
+	}
+
+	if got, known := taxonomy.Canonical("  Elixir "); got != "Elixir" || known {
+		t.Errorf("Canonical(unknown) = %q, %v; want the trimmed name", got, known)
+	}
+}
+
+func TestCanonicalSkillsMergesSpellings(t *testing.T) {
+	taxonomy := NewTaxonomy(Builtin)
+	got := taxonomy.CanonicalSkills(map[string]models.SkillLevel{
+		"JS":         {Name: "JS", Level: 2, Verified: true},
+		"javascript": {Name: "javascript", Level: 4},
+		"Golang":     {Name: "Golang", Level: 3},
+	})
+
+	want := map[string]models.SkillLevel{
+		"JavaScript": {Name: "JavaScript", Level: 4, Verified: true},
+		"Go":         {Name: "Go", Level: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CanonicalSkills = %v, want %v", got, want)
+	}
+	for name, skill := range want {
+		if got[name] != skill {
+			t.Errorf("CanonicalSkills[%s] = %+v, want %+v", name, got[name], skill)
+		}
+	}
+
+	levels := taxonomy.CanonicalLevels(map[string]int{"js": 2, "JavaScript": 3})
+	if len(levels) != 1 || levels["JavaScript"] != 3 {
+		t.Errorf("CanonicalLevels = %v, want JavaScript: 3", levels)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	taxonomy := NewTaxonomy(Builtin)
+	revision := taxonomy.Revision()
+
+	// Незнакомый навык становится синонимом известного
+	if target, err := taxonomy.Merge("жабаскрипт", "js"); err != nil || target != "JavaScript" {
+		t.Fatalf("Merge = %q, %v; want JavaScript", target, err)
+	}
+	if got, _ := taxonomy.Canonical("Жабаскрипт"); got != "JavaScript" {
+		t.Errorf("Canonical after Merge = %q", got)
+	}
+	if taxonomy.Revision() == revision {
+		t.Error("Revision did not change after Merge")
+	}
+
+	// Канонический навык переносится к другому вместе с синонимами
+	if _, err := taxonomy.Merge("Vue", "React"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if got, _ := taxonomy.Canonical("вью"); got != "React" {
+		t.Errorf("synonym of merged Vue = %q, want React", got)
+	}
+
+	// Синоним переносится один, без остальных синонимов своего навыка
+	if _, err := taxonomy.Merge("ES6", "TypeScript"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if got, _ := taxonomy.Canonical("ES6"); got != "TypeScript" {
+		t.Errorf("Canonical(ES6) = %q, want TypeScript", got)
+	}
+	if got, _ := taxonomy.Canonical("JS"); got != "JavaScript" {
+		t.Errorf("Canonical(JS) = %q, want JavaScript: moving one synonym must not move the skill", got)
+	}
+
+	if _, err := taxonomy.Merge(" . ", "Go"); err == nil {
+		t.Error("Merge accepted an empty alias")
+	}
+}
+
+func TestSetStoreOverridesBuiltin(t *testing.T) {
+	store := profile.NewInMemoryStorage()
+	if err := store.SaveSkillAlias("Node", "Node-RED"); err != nil {
+		t.Fatal(err)
+	}
+
+	taxonomy := NewTaxonomy(Builtin)
+	// Правка до SetStore остается только в памяти
+	if _, err := taxonomy.Merge("эрланг", "Erlang"); err != nil {
+		t.Fatal(err)
+	}
+	taxonomy.SetStore(store)
+
+	// Сохраненная правка администратора сильнее встроенного синонима
+	if got, _ := taxonomy.Canonical("node"); got != "Node-RED" {
+		t.Errorf("Canonical(node) = %q, want the stored Node-RED", got)
+	}
+	if got, _ := taxonomy.Canonical("NodeJS"); got != "Node.js" {
+		t.Errorf("Canonical(NodeJS) = %q, want Node.js", got)
+	}
+	if len(store.ListSkillAliases()) != 1 {
+		t.Errorf("edit made before SetStore was saved: %v", store.ListSkillAliases())
+	}
+
+	// Правки после SetStore сохраняются и применяются к новой таксономии
+	if got := taxonomy.Resolve("Эликсир"); got != "Эликсир" {
+		t.Errorf("Resolve(unknown) = %q", got)
+	}
+	if unknown := taxonomy.Unknown(); len(unknown) != 1 || unknown[0].Name != "Эликсир" {
+		t.Fatalf("Unknown = %+v, want Эликсир", unknown)
+	}
+	if _, err := taxonomy.Merge("Эликсир", "Elixir"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if unknown := taxonomy.Unknown(); len(unknown) != 0 {
+		t.Errorf("Unknown after Merge = %+v, want none", unknown)
+	}
+
+	restored := NewTaxonomy(Builtin)
+	restored.SetStore(store)
+	for name, want := range map[string]string{"эликсир": "Elixir", "Node": "Node-RED", "эрланг": "эрланг"} {
+		if got, _ := restored.Canonical(name); got != want {
+			t.Errorf("restored Canonical(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package usage
+
+import (
+	"context"
+
+	"viget-mvp/pkg/gpt"
+)
+
+// budgeted не пропускает к модели запросы пользователя, исчерпавшего лимит
+type budgeted struct {
+	llm     gpt.LLM
+	tracker *Tracker
+}
+
+// Wrap возвращает модель, которая перед каждым запросом проверяет дневной
+// лимит пользователя из контекста (gpt.WithUser)
+func (t *Tracker) Wrap(llm gpt.LLM) gpt.LLM {
+	return &budgeted{llm: llm, tracker: t}
+}
+
+func (b *budgeted) SendRequestContext(ctx context.Context, prompt string) (string, error) {
+	if err := b.tracker.Check(gpt.UserFrom(ctx)); err != nil {
+		return "", err
+	}
+	return b.llm.SendRequestContext(ctx, prompt)
+}
+
+// SendJSONRequestContext сохраняет JSON mode обернутой модели, если он есть
+func (b *budgeted) SendJSONRequestContext(ctx context.Context, prompt string) (string, error) {
+	if err := b.tracker.Check(gpt.UserFrom(ctx)); err != nil {
+		return "", err
+	}
+	if requester, ok := b.llm.(gpt.JSONRequester); ok {
+		return requester.SendJSONRequestContext(ctx, prompt)
+	}
+	return b.llm.SendRequestContext(ctx, prompt)
+}
+
+func (b *budgeted) ChatContext(ctx context.Context, messages []gpt.Message) (string, error) {
+	if err := b.tracker.Check(gpt.UserFrom(ctx)); err != nil {
+		return "", err
+	}
+	return gpt.Chat(ctx, b.llm, messages)
+}
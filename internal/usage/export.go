@@ -0,0 +1,32 @@
+package usage
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV выгружает расход за день day ("" — за все хранимые дни) в CSV
+func (t *Tracker) WriteCSV(w io.Writer, day string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"day", "user_id", "feature", "requests", "prompt_tokens", "completion_tokens", "total_tokens", "cost_usd"}); err != nil {
+		return err
+	}
+	for _, r := range t.Records(day) {
+		row := []string{
+			r.Day,
+			strconv.FormatInt(r.UserID, 10),
+			r.Feature,
+			strconv.Itoa(r.Requests),
+			strconv.Itoa(r.PromptTokens),
+			strconv.Itoa(r.CompletionTokens),
+			strconv.Itoa(r.Tokens()),
+			strconv.FormatFloat(r.Cost, 'f', 6, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
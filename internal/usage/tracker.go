@@ -0,0 +1,206 @@
+// Package usage учитывает расход токенов модели по пользователям и функциям
+// бота, оценивает стоимость и ограничивает дневной расход пользователя.
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"viget-mvp/pkg/gpt"
+)
+
+// ErrBudgetExceeded — пользователь израсходовал дневной лимит токенов
+var ErrBudgetExceeded = errors.New("usage: daily token budget exceeded")
+
+// DayFormat — формат дня в отчетах и выгрузке
+const DayFormat = "2006-01-02"
+
+// retentionDays — сколько дней хранится статистика в памяти
+const retentionDays = 90
+
+// Price — стоимость в долларах за миллион токенов промпта и ответа
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Cost оценивает стоимость запроса
+func (p Price) Cost(u gpt.Usage) float64 {
+	return (float64(u.PromptTokens)*p.Input + float64(u.CompletionTokens)*p.Output) / 1e6
+}
+
+// Totals — суммарный расход
+type Totals struct {
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+}
+
+func (t Totals) Tokens() int {
+	return t.PromptTokens + t.CompletionTokens
+}
+
+func (t *Totals) Add(o Totals) {
+	t.Requests += o.Requests
+	t.PromptTokens += o.PromptTokens
+	t.CompletionTokens += o.CompletionTokens
+	t.Cost += o.Cost
+}
+
+// Record — расход пользователя на одну функцию за день
+type Record struct {
+	Day     string
+	UserID  int64
+	Feature string
+	Totals
+}
+
+type recordKey struct {
+	day     string
+	userID  int64
+	feature string
+}
+
+// Tracker собирает расход из gpt.UsageHook. Пользователь и функция берутся
+// из контекста запроса (gpt.WithUser, gpt.WithFeature). Статистика хранится
+// в памяти и после перезапуска начинается заново.
+type Tracker struct {
+	mutex sync.Mutex
+	price Price
+	// prices — цены отдельных моделей (например, векторных представлений)
+	prices     map[string]Price
+	dailyLimit int
+	records    map[recordKey]*Totals
+	// spent — токены пользователя за день, для проверки лимита
+	spent map[string]map[int64]int
+	now   func() time.Time
+}
+
+// NewTracker создает учет с ценой price и дневным лимитом токенов
+// на пользователя dailyLimit (0 — без лимита)
+func NewTracker(price Price, dailyLimit int) *Tracker {
+	return &Tracker{
+		price:      price,
+		prices:     make(map[string]Price),
+		dailyLimit: dailyLimit,
+		records:    make(map[recordKey]*Totals),
+		spent:      make(map[string]map[int64]int),
+		now:        time.Now,
+	}
+}
+
+// SetPrice задает цену запросов к модели model; остальные модели считаются
+// по цене из NewTracker
+func (t *Tracker) SetPrice(model string, price Price) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.prices[model] = price
+}
+
+// Record учитывает расход одного запроса; подходит как gpt.UsageHook
+func (t *Tracker) Record(ctx context.Context, model string, u gpt.Usage) {
+	if u.PromptTokens == 0 && u.CompletionTokens == 0 && u.TotalTokens > 0 {
+		// Провайдер сообщил только общий расход — считаем его промптом
+		u.PromptTokens = u.TotalTokens
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	day := t.now().Format(DayFormat)
+	if _, ok := t.spent[day]; !ok {
+		t.prune(day)
+		t.spent[day] = make(map[int64]int)
+	}
+
+	price, ok := t.prices[model]
+	if !ok {
+		price = t.price
+	}
+
+	key := recordKey{day: day, userID: gpt.UserFrom(ctx), feature: gpt.FeatureFrom(ctx)}
+	totals, ok := t.records[key]
+	if !ok {
+		totals = &Totals{}
+		t.records[key] = totals
+	}
+	totals.Add(Totals{
+		Requests:         1,
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		Cost:             price.Cost(u),
+	})
+	t.spent[day][key.userID] += u.PromptTokens + u.CompletionTokens
+}
+
+// prune удаляет статистику старше retentionDays
+func (t *Tracker) prune(today string) {
+	now, err := time.ParseInLocation(DayFormat, today, time.Local)
+	if err != nil {
+		return
+	}
+	oldest := now.AddDate(0, 0, -retentionDays).Format(DayFormat)
+	for key := range t.records {
+		if key.day < oldest {
+			delete(t.records, key)
+		}
+	}
+	for day := range t.spent {
+		if day < oldest {
+			delete(t.spent, day)
+		}
+	}
+}
+
+// Check возвращает ErrBudgetExceeded, если пользователь исчерпал лимит на сегодня.
+// Запросы без пользователя (userID 0) не ограничиваются.
+func (t *Tracker) Check(userID int64) error {
+	if t.dailyLimit <= 0 || userID == 0 {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	spent := t.spent[t.now().Format(DayFormat)][userID]
+	if spent >= t.dailyLimit {
+		return fmt.Errorf("user %d: %w (%d of %d tokens)", userID, ErrBudgetExceeded, spent, t.dailyLimit)
+	}
+	return nil
+}
+
+// Records возвращает расход за день day ("" — за все хранимые дни),
+// упорядоченный по дню, пользователю и функции
+func (t *Tracker) Records(day string) []Record {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var records []Record
+	for key, totals := range t.records {
+		if day != "" && key.day != day {
+			continue
+		}
+		records = append(records, Record{Day: key.day, UserID: key.userID, Feature: key.feature, Totals: *totals})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		return a.Feature < b.Feature
+	})
+	return records
+}
+
+// Today — текущий день в формате DayFormat
+func (t *Tracker) Today() string {
+	return t.now().Format(DayFormat)
+}
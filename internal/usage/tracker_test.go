@@ -0,0 +1,136 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"viget-mvp/pkg/gpt"
+)
+
+func newTestTracker(limit int, now *time.Time) *Tracker {
+	t := NewTracker(Price{Input: 10, Output: 30}, limit)
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func userContext(userID int64, feature string) context.Context {
+	return gpt.WithFeature(gpt.WithUser(context.Background(), userID), feature)
+}
+
+func TestWrapRejectsUserOverLimit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	tracker := newTestTracker(100, &now)
+
+	fake := gpt.NewFakeClient("ok")
+	llm := tracker.Wrap(fake)
+
+	tracker.Record(userContext(1, "profile"), "gpt-4", gpt.Usage{PromptTokens: 80, CompletionTokens: 20})
+
+	_, err := llm.SendRequestContext(userContext(1, "profile"), "вопрос")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("over-limit request: err = %v, want ErrBudgetExceeded", err)
+	}
+	if _, err := gpt.Chat(userContext(1, "chat"), llm, []gpt.Message{{Role: "user", Content: "привет"}}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("over-limit chat: err = %v, want ErrBudgetExceeded", err)
+	}
+	if len(fake.Prompts()) != 0 {
+		t.Errorf("rejected requests reached the model: %q", fake.Prompts())
+	}
+
+	// Лимит личный: другой пользователь и запросы без пользователя проходят
+	if _, err := llm.SendRequestContext(userContext(2, "profile"), "вопрос"); err != nil {
+		t.Errorf("other user: %v", err)
+	}
+	if _, err := llm.SendRequestContext(context.Background(), "вопрос"); err != nil {
+		t.Errorf("request without user: %v", err)
+	}
+}
+
+func TestLimitResetsNextDay(t *testing.T) {
+	now := time.Date(2025, 3, 1, 23, 59, 0, 0, time.Local)
+	tracker := newTestTracker(100, &now)
+
+	tracker.Record(userContext(1, "task"), "gpt-4", gpt.Usage{PromptTokens: 150})
+	if err := tracker.Check(1); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Check = %v, want ErrBudgetExceeded", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := tracker.Check(1); err != nil {
+		t.Fatalf("Check on the next day = %v, want nil", err)
+	}
+	if tracker.Today() != "2025-03-02" {
+		t.Errorf("Today = %s, want 2025-03-02", tracker.Today())
+	}
+
+	// Вчерашний расход остается в отчете
+	tracker.Record(userContext(1, "task"), "gpt-4", gpt.Usage{PromptTokens: 10})
+	if records := tracker.Records("2025-03-01"); len(records) != 1 || records[0].PromptTokens != 150 {
+		t.Errorf("Records(yesterday) = %+v", records)
+	}
+	if records := tracker.Records(""); len(records) != 2 {
+		t.Errorf("Records(all) = %+v, want both days", records)
+	}
+}
+
+func TestOldDaysArePruned(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	tracker := newTestTracker(0, &now)
+
+	tracker.Record(userContext(1, "task"), "gpt-4", gpt.Usage{PromptTokens: 10})
+	now = now.AddDate(0, 0, retentionDays+1)
+	tracker.Record(userContext(1, "task"), "gpt-4", gpt.Usage{PromptTokens: 10})
+
+	if records := tracker.Records(""); len(records) != 1 || records[0].Day != tracker.Today() {
+		t.Errorf("Records = %+v, want only today", records)
+	}
+}
+
+func TestRecordPricesPerModel(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	tracker := newTestTracker(0, &now)
+	tracker.SetPrice("text-embedding-3-small", Price{Input: 0.02})
+
+	tracker.Record(userContext(1, "task"), "gpt-4", gpt.Usage{PromptTokens: 1000, CompletionTokens: 1000})
+	tracker.Record(userContext(1, "embedding"), "text-embedding-3-small", gpt.Usage{TotalTokens: 1_000_000})
+
+	records := tracker.Records("")
+	if len(records) != 2 {
+		t.Fatalf("Records = %+v, want 2", records)
+	}
+	if got := records[0]; got.Feature != "embedding" || got.PromptTokens != 1_000_000 || got.Cost != 0.02 {
+		t.Errorf("embedding record = %+v, want 1M prompt tokens at $0.02", got)
+	}
+	if got := records[1]; got.Feature != "task" || got.Cost != 0.04 {
+		t.Errorf("chat record = %+v, want $0.04 at the default price", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	tracker := newTestTracker(0, &now)
+	tracker.Record(userContext(2, "task"), "gpt-4", gpt.Usage{PromptTokens: 100, CompletionTokens: 50})
+	tracker.Record(userContext(1, "profile"), "gpt-4", gpt.Usage{PromptTokens: 1000})
+	tracker.Record(userContext(1, "profile"), "gpt-4", gpt.Usage{PromptTokens: 1000})
+	now = now.AddDate(0, 0, 1)
+	tracker.Record(userContext(1, "profile"), "gpt-4", gpt.Usage{PromptTokens: 1})
+
+	var buf bytes.Buffer
+	if err := tracker.WriteCSV(&buf, "2025-03-01"); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"day,user_id,feature,requests,prompt_tokens,completion_tokens,total_tokens,cost_usd",
+		"2025-03-01,1,profile,2,2000,0,2000,0.020000",
+		"2025-03-01,2,task,1,100,50,150,0.002500",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,340 @@
+// internal/vibot/answers.go
+package vibot
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError — ответ не прошел проверку; Message показывается пользователю
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Ответы, которыми можно пропустить необязательный вопрос
+var skipAnswers = map[string]bool{
+	"-":          true,
+	"—":          true,
+	"пропустить": true,
+	"skip":       true,
+}
+
+// IsSkip сообщает, что пользователь решил пропустить вопрос
+func IsSkip(raw string) bool {
+	return skipAnswers[strings.ToLower(strings.TrimSpace(raw))]
+}
+
+// ParseAnswer проверяет ответ по типу вопроса и возвращает разобранное значение:
+//
+//	text     — string
+//	number   — int (целое в диапазоне Min..Max)
+//	currency — int, сумма в рублях ("50к", "1,5 млн", "30 000 ₽")
+//	deadline — string в формате 2006-01-02 ("14 дней", "2 недели", "25.08", "завтра")
+//	choice   — string, значение одного из Options (по тексту или номеру);
+//	           []string при Multiple
+//
+// Для пропущенного необязательного вопроса возвращается nil без ошибки.
+func ParseAnswer(q QuestionTemplate, raw string, now time.Time) (interface{}, error) {
+	answer := strings.TrimSpace(raw)
+	if answer == "" {
+		return nil, invalid("Пожалуйста, дайте ответ на вопрос.")
+	}
+	if IsSkip(answer) {
+		if q.Required {
+			return nil, invalid("Это обязательный вопрос, его нельзя пропустить.")
+		}
+		return nil, nil
+	}
+
+	var value interface{}
+	var err error
+
+	switch q.Type {
+	case "number":
+		value, err = parseNumber(q, answer)
+	case "currency":
+		value, err = parseCurrency(q, answer)
+	case "deadline":
+		value, err = parseDeadline(answer, now)
+	case "choice":
+		value, err = parseChoice(q, answer)
+	default:
+		value = answer
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if q.Validate != nil && !q.Validate(answer) {
+		return nil, invalid("Ответ не подходит к вопросу, попробуйте сформулировать иначе.")
+	}
+
+	return value, nil
+}
+
+var numberPattern = regexp.MustCompile(`-?\d+(?:[.,]\d+)?`)
+
+func parseNumber(q QuestionTemplate, answer string) (int, error) {
+	match := numberPattern.FindString(answer)
+	if match == "" {
+		return 0, invalid("Введите число%s.", rangeHint(q))
+	}
+
+	f, err := strconv.ParseFloat(strings.Replace(match, ",", ".", 1), 64)
+	if err != nil || f != math.Trunc(f) {
+		return 0, invalid("Введите целое число%s.", rangeHint(q))
+	}
+
+	n := int(f)
+	if !inRange(q, float64(n)) {
+		return 0, invalid("Число должно быть%s.", rangeHint(q))
+	}
+	return n, nil
+}
+
+// currencyPattern — сумма и необязательный множитель. После множителя не
+// должно быть буквы, иначе "50000 максимум" читается как 50000 миллионов.
+var currencyPattern = regexp.MustCompile(`(\d[\d\s]*(?:[.,]\d+)?)\s*(?:(к|k|тыс\.?|тысяч[аи]?|м|m|млн|миллион(?:а|ов)?)(?:\P{L}|$))?`)
+
+// rangeSeparators — что стоит между границами диапазона: "30-50к", "от 30 до 50к"
+var rangeSeparators = map[string]bool{"-": true, "–": true, "—": true, "до": true}
+
+func parseCurrency(q QuestionTemplate, answer string) (int, error) {
+	text := strings.ToLower(answer)
+	matches := currencyPattern.FindAllStringSubmatchIndex(text, 2)
+	if matches == nil {
+		return 0, invalid("Укажите сумму в рублях, например: 50000 или 50к.")
+	}
+
+	// Из диапазона берется верхняя граница; множитель, указанный только
+	// у нее ("от 30 до 50к"), относится к обеим
+	m := matches[0]
+	suffix := submatch(text, m, 2)
+	if len(matches) == 2 {
+		end := m[3]
+		if m[5] > end {
+			end = m[5]
+		}
+		if rangeSeparators[strings.TrimSpace(text[end:matches[1][0]])] {
+			m = matches[1]
+			if upper := submatch(text, m, 2); upper != "" || suffix == "" {
+				suffix = upper
+			}
+		}
+	}
+
+	digits := strings.Join(strings.Fields(submatch(text, m, 1)), "")
+	amount, err := strconv.ParseFloat(strings.Replace(digits, ",", ".", 1), 64)
+	if err != nil {
+		return 0, invalid("Укажите сумму в рублях, например: 50000 или 50к.")
+	}
+
+	switch strings.TrimSuffix(suffix, ".") {
+	case "к", "k", "тыс", "тысяч", "тысяча", "тысячи":
+		amount *= 1000
+	case "м", "m", "млн", "миллион", "миллиона", "миллионов":
+		amount *= 1000000
+	}
+
+	if amount <= 0 {
+		return 0, invalid("Сумма должна быть больше нуля.")
+	}
+	if !inRange(q, amount) {
+		return 0, invalid("Сумма должна быть%s ₽.", rangeHint(q))
+	}
+	return int(math.Round(amount)), nil
+}
+
+// submatch возвращает группу n совпадения m или "", если группа не участвовала
+func submatch(text string, m []int, n int) string {
+	if m[2*n] < 0 {
+		return ""
+	}
+	return text[m[2*n]:m[2*n+1]]
+}
+
+var (
+	daysPattern   = regexp.MustCompile(`^(\d+)\s*(д|дн|дня|дней|день|day|days)?\.?$`)
+	periodPattern = regexp.MustCompile(`^(\d+)?\s*(недел[юяиь]|нед\.?|месяц(?:а|ев)?|мес\.?)$`)
+	datePattern   = regexp.MustCompile(`(\d{1,2})\.(\d{1,2})(?:\.(\d{2,4}))?`)
+	isoPattern    = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+)
+
+const deadlineFormat = "2006-01-02"
+
+func parseDeadline(answer string, now time.Time) (string, error) {
+	text := strings.ToLower(strings.TrimSpace(answer))
+	text = strings.TrimPrefix(text, "через ")
+	text = strings.TrimPrefix(text, "за ")
+	text = strings.TrimPrefix(text, "до ")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var deadline time.Time
+	switch {
+	case text == "завтра":
+		deadline = today.AddDate(0, 0, 1)
+	case text == "послезавтра":
+		deadline = today.AddDate(0, 0, 2)
+	case daysPattern.MatchString(text):
+		days, _ := strconv.Atoi(daysPattern.FindStringSubmatch(text)[1])
+		deadline = today.AddDate(0, 0, days)
+	case periodPattern.MatchString(text):
+		m := periodPattern.FindStringSubmatch(text)
+		n := 1
+		if m[1] != "" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		if strings.HasPrefix(m[2], "нед") {
+			deadline = today.AddDate(0, 0, 7*n)
+		} else {
+			deadline = today.AddDate(0, n, 0)
+		}
+	case isoPattern.MatchString(text):
+		d, err := time.ParseInLocation(deadlineFormat, isoPattern.FindString(text), now.Location())
+		if err != nil {
+			return "", invalid("Не удалось разобрать дату. Укажите, например: 14 дней или 25.08.2025.")
+		}
+		deadline = d
+	case datePattern.MatchString(text):
+		m := datePattern.FindStringSubmatch(text)
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year := today.Year()
+		if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+			if year < 100 {
+				year += 2000
+			}
+		}
+		deadline = time.Date(year, time.Month(month), day, 0, 0, 0, 0, now.Location())
+		if deadline.Day() != day || int(deadline.Month()) != month {
+			return "", invalid("Такой даты не существует. Укажите, например: 25.08.2025.")
+		}
+		// Дата без года в прошлом — значит, имеется в виду следующий год
+		if m[3] == "" && deadline.Before(today) {
+			deadline = deadline.AddDate(1, 0, 0)
+		}
+	default:
+		return "", invalid("Укажите срок в днях или дату, например: 14 дней, 2 недели или 25.08.2025.")
+	}
+
+	if !deadline.After(today) {
+		return "", invalid("Срок должен быть в будущем.")
+	}
+	return deadline.Format(deadlineFormat), nil
+}
+
+func parseChoice(q QuestionTemplate, answer string) (interface{}, error) {
+	if !q.Multiple {
+		option, ok := findOption(q, answer)
+		if !ok {
+			return nil, invalid("Выберите один из вариантов: %s.", optionLabels(q))
+		}
+		return option.OptionValue(), nil
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for _, part := range strings.FieldsFunc(answer, isChoiceSeparator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		option, ok := findOption(q, part)
+		if !ok {
+			return nil, invalid("Вариант «%s» не подходит. Выберите из: %s.", part, optionLabels(q))
+		}
+		if !seen[option.OptionValue()] {
+			seen[option.OptionValue()] = true
+			values = append(values, option.OptionValue())
+		}
+	}
+	if len(values) == 0 {
+		return nil, invalid("Выберите хотя бы один вариант.")
+	}
+	return values, nil
+}
+
+// isChoiceSeparator разделяет варианты в текстовом ответе на множественный
+// выбор, поэтому в их подписях и значениях эти символы запрещены
+func isChoiceSeparator(r rune) bool {
+	return r == ',' || r == ';' || r == '\n'
+}
+
+// findOption ищет вариант по номеру, тексту или значению
+func findOption(q QuestionTemplate, answer string) (Option, bool) {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(q.Options) {
+		return q.Options[n-1], true
+	}
+	for _, option := range q.Options {
+		if strings.EqualFold(option.Label, answer) || strings.EqualFold(option.OptionValue(), answer) {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+func optionLabels(q QuestionTemplate) string {
+	labels := make([]string, len(q.Options))
+	for i, option := range q.Options {
+		labels[i] = option.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// choiceContext собирает контекст выбранных вариантов
+func choiceContext(q QuestionTemplate, parsed interface{}) map[string]interface{} {
+	var values []string
+	switch v := parsed.(type) {
+	case string:
+		values = []string{v}
+	case []string:
+		values = v
+	}
+
+	context := make(map[string]interface{})
+	for _, value := range values {
+		option, ok := findOption(q, value)
+		if !ok {
+			continue
+		}
+		for k, v := range option.Context {
+			context[k] = v
+		}
+	}
+	return context
+}
+
+func inRange(q QuestionTemplate, v float64) bool {
+	if q.Min != nil && v < *q.Min {
+		return false
+	}
+	if q.Max != nil && v > *q.Max {
+		return false
+	}
+	return true
+}
+
+func rangeHint(q QuestionTemplate) string {
+	switch {
+	case q.Min != nil && q.Max != nil:
+		return fmt.Sprintf(" от %g до %g", *q.Min, *q.Max)
+	case q.Min != nil:
+		return fmt.Sprintf(" не меньше %g", *q.Min)
+	case q.Max != nil:
+		return fmt.Sprintf(" не больше %g", *q.Max)
+	}
+	return ""
+}
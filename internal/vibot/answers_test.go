@@ -0,0 +1,81 @@
+package vibot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAnswerCurrency(t *testing.T) {
+	q := QuestionTemplate{ID: "budget", Type: "currency", Required: true, Min: bound(500)}
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		answer string
+		want   int
+	}{
+		{"50000", 50000},
+		{"50 000 ₽", 50000},
+		{"50к", 50000},
+		{"50 k", 50000},
+		{"50 тыс.", 50000},
+		{"50 тысяч рублей", 50000},
+		{"1,5 млн", 1500000},
+		{"2 миллиона", 2000000},
+		{"3м", 3000000},
+		{"3 m", 3000000},
+		{"50000 максимум", 50000},
+		{"50 000 можно обсудить", 50000},
+		{"100 000 минимум", 100000},
+		{"около 70к, максимум 90к", 70000},
+		{"30 тыс, можно меньше", 30000},
+		{"от 30 до 50к", 50000},
+		{"30-50 тыс", 50000},
+		{"20к – 40к", 40000},
+		{"от 30к до 50", 50000},
+		{"1 000 - 1 500 000", 1500000},
+	}
+	for _, tt := range tests {
+		got, err := ParseAnswer(q, tt.answer, now)
+		if err != nil {
+			t.Errorf("ParseAnswer(%q): %v", tt.answer, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAnswer(%q) = %v, want %d", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestParseAnswerCurrencyInvalid(t *testing.T) {
+	q := QuestionTemplate{ID: "budget", Type: "currency", Required: true, Min: bound(500)}
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, answer := range []string{"", "договорная", "100", "0", "-"} {
+		if got, err := ParseAnswer(q, answer, now); err == nil {
+			t.Errorf("ParseAnswer(%q) = %v, want error", answer, got)
+		}
+	}
+}
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+func TestParseAnswerNumberZeroBounds(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	q := QuestionTemplate{ID: "delta", Type: "number", Required: true, Min: bound(-10), Max: bound(0)}
+
+	for answer, want := range map[string]int{"0": 0, "-3": -3, "-10": -10} {
+		if got, err := ParseAnswer(q, answer, now); err != nil || got != want {
+			t.Errorf("ParseAnswer(%q) = %v, %v; want %d", answer, got, err, want)
+		}
+	}
+	if got, err := ParseAnswer(q, "5", now); err == nil {
+		t.Errorf("ParseAnswer(5) = %v, want error for max: 0", got)
+	}
+
+	q = QuestionTemplate{ID: "count", Type: "number", Required: true, Min: bound(0)}
+	if got, err := ParseAnswer(q, "-1", now); err == nil {
+		t.Errorf("ParseAnswer(-1) = %v, want error for min: 0", got)
+	}
+}
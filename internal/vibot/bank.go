@@ -0,0 +1,291 @@
+// internal/vibot/bank.go
+package vibot
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed banks/default.yaml
+var defaultBank []byte
+
+// Описание банка вопросов в файле (см. banks/default.yaml)
+type bankFile struct {
+	Version    int                      `yaml:"version" json:"version"`
+	Interviews map[string]interviewFile `yaml:"interviews" json:"interviews"`
+}
+
+type interviewFile struct {
+	Questions []questionFile `yaml:"questions" json:"questions"`
+}
+
+type questionFile struct {
+	ID        string        `yaml:"id" json:"id"`
+	Text      string        `yaml:"text" json:"text"`
+	Type      string        `yaml:"type" json:"type"`
+	Required  bool          `yaml:"required" json:"required"`
+	Min       *float64      `yaml:"min" json:"min"`
+	Max       *float64      `yaml:"max" json:"max"`
+	MinLength int           `yaml:"min_length" json:"min_length"`
+	MaxLength int           `yaml:"max_length" json:"max_length"`
+	Pattern   string        `yaml:"pattern" json:"pattern"`
+	Options   []optionFile  `yaml:"options" json:"options"`
+	Multiple  bool          `yaml:"multiple" json:"multiple"`
+	Variants  []variantFile `yaml:"variants" json:"variants"`
+	Next      []nextFile    `yaml:"next" json:"next"`
+}
+
+type nextFile struct {
+	When map[string]string `yaml:"when" json:"when"`
+	Goto string            `yaml:"goto" json:"goto"`
+}
+
+type optionFile struct {
+	Label   string                 `yaml:"label" json:"label"`
+	Value   string                 `yaml:"value" json:"value"`
+	Context map[string]interface{} `yaml:"context" json:"context"`
+}
+
+type variantFile struct {
+	When map[string]string `yaml:"when" json:"when"`
+	Text string            `yaml:"text" json:"text"`
+}
+
+var (
+	supportedTypes      = map[string]bool{"text": true, "number": true, "currency": true, "deadline": true, "choice": true}
+	requiredInterviews  = []string{"profile", "task"}
+	supportedBankFormat = 1
+)
+
+// LoadQuestionBank читает банк вопросов из YAML или JSON файла и проверяет его.
+func LoadQuestionBank(path string) (*QuestionBank, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+
+	bank, err := parseQuestionBank(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return bank, nil
+}
+
+func parseQuestionBank(data []byte, format string) (*QuestionBank, error) {
+	var file bankFile
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return nil, err
+		}
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return nil, err
+		}
+	}
+
+	return file.build()
+}
+
+// build проверяет описание целиком и собирает все ошибки, а не только первую
+func (f bankFile) build() (*QuestionBank, error) {
+	var errs []error
+
+	if f.Version != supportedBankFormat {
+		errs = append(errs, fmt.Errorf("unsupported version %d (want %d)", f.Version, supportedBankFormat))
+	}
+	for _, name := range requiredInterviews {
+		if len(f.Interviews[name].Questions) == 0 {
+			errs = append(errs, fmt.Errorf("interview %q has no questions", name))
+		}
+	}
+
+	bank := &QuestionBank{interviews: make(map[string][]QuestionTemplate)}
+	for name, interview := range f.Interviews {
+		ids := make(map[string]bool)
+		for i, qf := range interview.Questions {
+			where := fmt.Sprintf("%s question #%d", name, i+1)
+			if qf.ID != "" {
+				where = fmt.Sprintf("%s question %q", name, qf.ID)
+			}
+
+			q, qerrs := qf.build()
+			for _, err := range qerrs {
+				errs = append(errs, fmt.Errorf("%s: %w", where, err))
+			}
+			if qf.ID != "" && ids[qf.ID] {
+				errs = append(errs, fmt.Errorf("%s: duplicate id", where))
+			}
+			ids[qf.ID] = true
+
+			bank.interviews[name] = append(bank.interviews[name], q)
+		}
+
+		// Переходы проверяем, когда известны все вопросы интервью
+		for _, qf := range interview.Questions {
+			for i, nf := range qf.Next {
+				if nf.Goto != "" && nf.Goto != EndNode && !ids[nf.Goto] {
+					errs = append(errs, fmt.Errorf("%s question %q: next #%d: unknown goto %q", name, qf.ID, i+1, nf.Goto))
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return bank, nil
+}
+
+func (qf questionFile) build() (QuestionTemplate, []error) {
+	var errs []error
+
+	if qf.ID == "" {
+		errs = append(errs, errors.New("id is required"))
+	}
+	if qf.ID == EndNode {
+		errs = append(errs, fmt.Errorf("id %q is reserved", EndNode))
+	}
+	if strings.TrimSpace(qf.Text) == "" {
+		errs = append(errs, errors.New("text is required"))
+	}
+	if !supportedTypes[qf.Type] {
+		errs = append(errs, fmt.Errorf("unknown type %q", qf.Type))
+	}
+	if qf.Min != nil && qf.Max != nil && *qf.Min > *qf.Max {
+		errs = append(errs, fmt.Errorf("min %g is greater than max %g", *qf.Min, *qf.Max))
+	}
+	if qf.MaxLength != 0 && qf.MinLength > qf.MaxLength {
+		errs = append(errs, fmt.Errorf("min_length %d is greater than max_length %d", qf.MinLength, qf.MaxLength))
+	}
+	if qf.Type == "choice" && len(qf.Options) == 0 {
+		errs = append(errs, errors.New("choice question needs options"))
+	}
+	if qf.Type != "choice" && (len(qf.Options) > 0 || qf.Multiple) {
+		errs = append(errs, errors.New("options and multiple are only allowed for choice"))
+	}
+
+	q := QuestionTemplate{
+		ID:       qf.ID,
+		Text:     qf.Text,
+		Required: qf.Required,
+		Type:     qf.Type,
+		Min:      qf.Min,
+		Max:      qf.Max,
+		Multiple: qf.Multiple,
+	}
+
+	labels := make(map[string]bool)
+	for i, of := range qf.Options {
+		if strings.TrimSpace(of.Label) == "" {
+			errs = append(errs, fmt.Errorf("option #%d: label is required", i+1))
+		}
+		if labels[of.Label] {
+			errs = append(errs, fmt.Errorf("option %q: duplicate label", of.Label))
+		}
+		if qf.Multiple && (strings.ContainsFunc(of.Label, isChoiceSeparator) || strings.ContainsFunc(of.Value, isChoiceSeparator)) {
+			errs = append(errs, fmt.Errorf("option %q: label and value of a multiple choice must not contain , ; or a line break", of.Label))
+		}
+		labels[of.Label] = true
+		q.Options = append(q.Options, Option{Label: of.Label, Value: of.Value, Context: of.Context})
+	}
+
+	for i, vf := range qf.Variants {
+		if len(vf.When) == 0 {
+			errs = append(errs, fmt.Errorf("variant #%d: when is required", i+1))
+		}
+		if strings.TrimSpace(vf.Text) == "" {
+			errs = append(errs, fmt.Errorf("variant #%d: text is required", i+1))
+		}
+		q.Variants = append(q.Variants, Variant{When: vf.When, Text: vf.Text})
+	}
+
+	for i, nf := range qf.Next {
+		if nf.Goto == "" {
+			errs = append(errs, fmt.Errorf("next #%d: goto is required", i+1))
+		}
+		q.Next = append(q.Next, Transition{When: nf.When, Goto: nf.Goto})
+	}
+
+	var pattern *regexp.Regexp
+	if qf.Pattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(qf.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("pattern: %w", err))
+		}
+	}
+
+	if pattern != nil || qf.MinLength > 0 || qf.MaxLength > 0 {
+		minLength, maxLength := qf.MinLength, qf.MaxLength
+		q.Validate = func(answer string) bool {
+			n := utf8.RuneCountInString(answer)
+			if n < minLength || (maxLength > 0 && n > maxLength) {
+				return false
+			}
+			return pattern == nil || pattern.MatchString(answer)
+		}
+	}
+
+	return q, errs
+}
+
+// Watch перечитывает файл банка при изменении, пока не закрыт stop.
+// Ошибочный файл не применяется — продолжает работать предыдущая версия.
+// Незавершенные интервью продолжаются с того же вопроса; если его убрали
+// из файла, интервью завершается на уже данных ответах.
+func (q *QuestionBank) Watch(path string, interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().After(lastMod) {
+			continue
+		}
+		lastMod = info.ModTime()
+
+		bank, err := LoadQuestionBank(path)
+		if err != nil {
+			log.Printf("question bank reload failed, keeping previous version: %v", err)
+			continue
+		}
+		q.replace(bank)
+		log.Printf("question bank reloaded from %s", path)
+	}
+}
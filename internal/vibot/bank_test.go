@@ -0,0 +1,71 @@
+package vibot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseQuestionBankRejectsInvalid(t *testing.T) {
+	const bank = `
+version: 1
+interviews:
+  profile:
+    questions:
+      - {id: name, text: "Имя?", type: text}
+      - {id: name, text: "Еще раз имя?", type: text}
+  task:
+    questions:
+      - {id: kind, text: "Что сделать?", type: choice, options: [{label: "Сайт"}], next: [{when: {answer: "Сайт"}, goto: budget}]}
+      - {id: days, text: "Срок?", type: number, min: 10, max: 1}
+`
+	_, err := parseQuestionBank([]byte(bank), "yaml")
+	if err == nil {
+		t.Fatal("invalid bank was accepted")
+	}
+
+	// Все ошибки собираются разом, а не только первая
+	for _, want := range []string{
+		`profile question "name": duplicate id`,
+		`task question "kind": next #1: unknown goto "budget"`,
+		`task question "days": min 10 is greater than max 1`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestParseQuestionBankRejectsMissingParts(t *testing.T) {
+	for name, bank := range map[string]string{
+		"unknown field":     "version: 1\ninterviews: {profile: {questions: [{id: a, text: a, type: text, hint: b}]}, task: {questions: [{id: a, text: a, type: text}]}}\n",
+		"wrong version":     "version: 2\ninterviews: {profile: {questions: [{id: a, text: a, type: text}]}, task: {questions: [{id: a, text: a, type: text}]}}\n",
+		"missing interview": "version: 1\ninterviews: {profile: {questions: [{id: a, text: a, type: text}]}}\n",
+		"unknown type":      "version: 1\ninterviews: {profile: {questions: [{id: a, text: a, type: date}]}, task: {questions: [{id: a, text: a, type: text}]}}\n",
+		"reserved id":       "version: 1\ninterviews: {profile: {questions: [{id: end, text: a, type: text}]}, task: {questions: [{id: a, text: a, type: text}]}}\n",
+	} {
+		if _, err := parseQuestionBank([]byte(bank), "yaml"); err == nil {
+			t.Errorf("%s: bank was accepted", name)
+		}
+	}
+}
+
+func TestLoadQuestionBankJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.json")
+	const bank = `{"version": 1, "interviews": {
+		"profile": {"questions": [{"id": "name", "text": "Имя?", "type": "text", "required": true}]},
+		"task": {"questions": [{"id": "title", "text": "Название?", "type": "text", "next": [{"goto": "end"}]}]}
+	}}`
+	if err := os.WriteFile(path, []byte(bank), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadQuestionBank(path)
+	if err != nil {
+		t.Fatalf("LoadQuestionBank: %v", err)
+	}
+	if first := loaded.First("task"); first != "title" {
+		t.Errorf("First(task) = %q, want title", first)
+	}
+}
@@ -0,0 +1,171 @@
+package vibot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"viget-mvp/internal/extractor"
+	"viget-mvp/internal/models"
+	"viget-mvp/pkg/gpt"
+)
+
+// ModeChat — интервью для профиля ведет модель в форме беседы
+const ModeChat = "chat"
+
+// chatOpening — первая реплика интервьюера; дальше вопросы задает модель
+const chatOpening = "Привет! Давайте познакомимся: как вас зовут и чем вы сейчас занимаетесь?"
+
+// ChatLimits ограничивают беседу: после MaxTurns ответов или MaxTokens токенов
+// интервью завершается на уже собранной информации
+type ChatLimits struct {
+	MaxTurns  int
+	MaxTokens int
+}
+
+var DefaultChatLimits = ChatLimits{MaxTurns: 12, MaxTokens: 30000}
+
+// SetChatMode включает беседу с моделью вместо анкеты для интервью профиля.
+// Начатые анкеты продолжаются анкетой.
+func (i *Interviewer) SetChatMode(limits ChatLimits) {
+	i.chat.Store(&limits)
+}
+
+func (i *Interviewer) chatLimits() ChatLimits {
+	if limits := i.chat.Load(); limits != nil {
+		return *limits
+	}
+	// Режим выключили, а беседы остались — доводим их с ограничениями по умолчанию
+	return DefaultChatLimits
+}
+
+func (i *Interviewer) isChat(userID int64) bool {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+	session := i.sessions.GetSession(userID)
+	return session != nil && session.Mode == ModeChat
+}
+
+// pendingReply — сохраненный ответ пользователя, ожидающий реплики интервьюера
+type pendingReply struct {
+	revision
+	transcript []models.ChatMessage
+}
+
+// chatAnswer сохраняет ответ пользователя и получает от модели следующий вопрос.
+// Ответ сохраняется до обращения к модели: если она недоступна, следующее
+// сообщение пользователя дополнит его, и запрос повторится.
+func (i *Interviewer) chatAnswer(userID int64, answer string, progress gpt.ProgressFunc) (string, bool, error) {
+	pending, reply, finished, err := i.prepareChat(userID, answer)
+	if pending == nil {
+		return reply, finished, err
+	}
+
+	ctx, cancel := context.WithTimeout(gpt.WithProgress(gpt.WithUser(context.Background(), userID), progress), llmTimeout)
+	defer cancel()
+
+	next, err := i.extractor.Converse(ctx, pending.key(userID), i.chatLimits().MaxTurns, pending.transcript)
+	if err != nil {
+		return "", false, err
+	}
+
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if !pending.matches(session) {
+		if session == nil {
+			return "", false, ErrSessionChanged
+		}
+		return "⚠️ Пока обрабатывался ответ, интервью изменилось. Текущий вопрос:\n\n" + i.formatQuestion(session), false, nil
+	}
+
+	session.Tokens += next.Tokens
+	if next.Message != "" {
+		session.Transcript = append(session.Transcript, models.ChatMessage{Role: gpt.RoleAssistant, Content: next.Message})
+	}
+	session.ChatDone = next.Done || session.Tokens >= i.chatLimits().MaxTokens
+	session.UpdatedAt = time.Now()
+	if err := i.sessions.SaveSession(session); err != nil {
+		return "", false, err
+	}
+
+	if session.ChatDone {
+		return "", true, nil
+	}
+	return i.formatQuestion(session), false, nil
+}
+
+// prepareChat записывает ответ в беседу. Если достигнут предел ходов или
+// токенов, интервью завершается без обращения к модели.
+func (i *Interviewer) prepareChat(userID int64, answer string) (pending *pendingReply, reply string, finished bool, err error) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil, "", false, fmt.Errorf("session not found")
+	}
+
+	// Беседа окончена, но профиль не извлечен — повторяем извлечение
+	if session.ChatDone {
+		return nil, "", true, nil
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, "⚠️ Напишите ответ текстом.\n\n" + i.formatQuestion(session), false, nil
+	}
+
+	limits := i.chatLimits()
+	session.Transcript = append(session.Transcript, models.ChatMessage{Role: gpt.RoleUser, Content: answer})
+	session.CurrentStep++
+	session.ChatDone = session.CurrentStep >= limits.MaxTurns || session.Tokens >= limits.MaxTokens
+	session.UpdatedAt = time.Now()
+	if err := i.sessions.SaveSession(session); err != nil {
+		return nil, "", false, err
+	}
+	if session.ChatDone {
+		return nil, "", true, nil
+	}
+
+	return &pendingReply{
+		revision:   revisionOf(session),
+		transcript: append([]models.ChatMessage(nil), session.Transcript...),
+	}, "", false, nil
+}
+
+// formatChat показывает последнюю реплику интервьюера
+func (i *Interviewer) formatChat(session *models.InterviewSession) string {
+	current, total := i.progress(session)
+	question := chatOpening
+	for n := len(session.Transcript) - 1; n >= 0; n-- {
+		if session.Transcript[n].Role == gpt.RoleAssistant {
+			question = session.Transcript[n].Content
+			break
+		}
+	}
+	return fmt.Sprintf("💬 Знакомство (вопрос %d/%d)\n\n%s", current, total, question)
+}
+
+// chatAnswers превращает беседу в вопросы и ответы для извлечения профиля.
+// Несколько сообщений пользователя подряд считаются одним ответом.
+func chatAnswers(transcript []models.ChatMessage) []extractor.Answer {
+	var answers []extractor.Answer
+	question, continued := "", false
+	for _, m := range transcript {
+		switch m.Role {
+		case gpt.RoleAssistant:
+			question, continued = m.Content, false
+		case gpt.RoleUser:
+			if continued {
+				answers[len(answers)-1].Answer += "\n" + m.Content
+				continue
+			}
+			answers = append(answers, extractor.Answer{Question: question, Answer: m.Content})
+			continued = true
+		}
+	}
+	return answers
+}
@@ -0,0 +1,38 @@
+package vibot
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSetChatModeWhileInterviewing(t *testing.T) {
+	i := newTestInterviewer(t, "{}")
+
+	var wg sync.WaitGroup
+	for user := int64(1); user <= 4; user++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			for n := 0; n < 20; n++ {
+				if err := i.StartInterview(userID, "profile"); err != nil {
+					t.Errorf("StartInterview: %v", err)
+					return
+				}
+				i.GetCurrentQuestion(userID)
+			}
+		}(user)
+	}
+	i.SetChatMode(ChatLimits{MaxTurns: 3, MaxTokens: 1000})
+	wg.Wait()
+
+	// Интервью, начатое после включения, ведется беседой
+	if err := i.StartInterview(9, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	if !i.isChat(9) {
+		t.Error("profile interview started after SetChatMode is not a chat")
+	}
+	if got := i.chatLimits().MaxTurns; got != 3 {
+		t.Errorf("MaxTurns = %d, want 3", got)
+	}
+}
@@ -0,0 +1,96 @@
+package vibot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"viget-mvp/internal/models"
+)
+
+// CurrentTemplate возвращает текущий вопрос и отмеченные варианты (для множественного выбора)
+func (i *Interviewer) CurrentTemplate(userID int64) (QuestionTemplate, []int, bool) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return QuestionTemplate{}, nil, false
+	}
+
+	template, ok := i.currentTemplate(session)
+	return template, append([]int(nil), session.Selected...), ok
+}
+
+// SelectOption отвечает на вопрос с одиночным выбором вариантом с номером index
+func (i *Interviewer) SelectOption(userID int64, index int) (string, bool, error) {
+	return i.answer(userID, func(_ *models.InterviewSession, template QuestionTemplate) (string, interface{}, error) {
+		if template.Type != "choice" || index < 0 || index >= len(template.Options) {
+			return "", nil, fmt.Errorf("invalid option %d", index)
+		}
+		option := template.Options[index]
+		if template.Multiple {
+			return option.Label, []string{option.OptionValue()}, nil
+		}
+		return option.Label, option.OptionValue(), nil
+	})
+}
+
+// ToggleOption отмечает или снимает вариант в вопросе с множественным выбором
+func (i *Interviewer) ToggleOption(userID int64, index int) ([]int, error) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found")
+	}
+
+	template, ok := i.currentTemplate(session)
+	if !ok || !template.Multiple || index < 0 || index >= len(template.Options) {
+		return nil, fmt.Errorf("invalid option %d", index)
+	}
+
+	selected := session.Selected[:0:0]
+	found := false
+	for _, idx := range session.Selected {
+		if idx == index {
+			found = true
+			continue
+		}
+		selected = append(selected, idx)
+	}
+	if !found {
+		selected = append(selected, index)
+		sort.Ints(selected)
+	}
+	session.Selected = selected
+
+	if err := i.sessions.SaveSession(session); err != nil {
+		return nil, err
+	}
+	return append([]int(nil), selected...), nil
+}
+
+// SubmitSelection отправляет отмеченные варианты как ответ на текущий вопрос.
+// Без отмеченных вариантов пользователь получит подсказку, как при пустом ответе.
+func (i *Interviewer) SubmitSelection(userID int64) (string, bool, error) {
+	return i.answer(userID, func(session *models.InterviewSession, template QuestionTemplate) (string, interface{}, error) {
+		if !template.Multiple {
+			return "", nil, fmt.Errorf("current question is not a multiple choice")
+		}
+
+		var labels, values []string
+		for _, idx := range session.Selected {
+			if idx >= 0 && idx < len(template.Options) {
+				labels = append(labels, template.Options[idx].Label)
+				values = append(values, template.Options[idx].OptionValue())
+			}
+		}
+		if len(values) == 0 {
+			return "", nil, nil
+		}
+		// Подпись — для истории ответов; значения переданы как есть
+		return strings.Join(labels, ", "), values, nil
+	})
+}
@@ -0,0 +1,75 @@
+package vibot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"viget-mvp/internal/extractor"
+	"viget-mvp/pkg/gpt"
+)
+
+const commaBank = `
+version: 1
+interviews:
+  profile:
+    questions:
+      - id: stack
+        text: "Стек?"
+        type: choice
+        multiple: true
+        options:
+          - {label: "Go", value: "go"}
+          - {label: "Python", value: "python"}
+      - id: place
+        text: "Где работаете?"
+        type: choice
+        options:
+          - {label: "Офис, гибрид", value: "office"}
+          - {label: "Удаленно", value: "remote"}
+  task:
+    questions:
+      - {id: title, text: "Название?", type: text}
+`
+
+func TestSubmitSelectionPassesValues(t *testing.T) {
+	bank, err := parseQuestionBank([]byte(commaBank), "yaml")
+	if err != nil {
+		t.Fatalf("parse bank: %v", err)
+	}
+	i := NewInterviewer(extractor.NewExtractor(gpt.NewFakeClient(`{}`)), nil)
+	i.SetQuestionBank(bank)
+
+	const userID = 7
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	for _, index := range []int{1, 0} {
+		if _, err := i.ToggleOption(userID, index); err != nil {
+			t.Fatalf("ToggleOption(%d): %v", index, err)
+		}
+	}
+	if _, _, err := i.SubmitSelection(userID); err != nil {
+		t.Fatalf("SubmitSelection: %v", err)
+	}
+	// Подпись с запятой в одиночном выборе не разбирается на части
+	if _, finished, err := i.SelectOption(userID, 0); err != nil || !finished {
+		t.Fatalf("SelectOption = finished %v, err %v", finished, err)
+	}
+
+	session := i.sessions.GetSession(userID)
+	if got, want := session.Parsed[answerKey(0)], []string{"go", "python"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("multiple choice parsed as %#v, want %#v", got, want)
+	}
+	if got := session.Parsed[answerKey(1)]; got != "office" {
+		t.Errorf("single choice parsed as %#v, want %q", got, "office")
+	}
+}
+
+func TestBankRejectsSeparatorsInMultipleChoice(t *testing.T) {
+	bank := strings.Replace(commaBank, `{label: "Python", value: "python"}`, `{label: "Python, Django", value: "python"}`, 1)
+	_, err := parseQuestionBank([]byte(bank), "yaml")
+	if err == nil || !strings.Contains(err.Error(), "must not contain") {
+		t.Errorf("parse bank err = %v, want a separator error", err)
+	}
+}
@@ -0,0 +1,102 @@
+package vibot
+
+import (
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+// SetSessionTTL задает срок жизни брошенной сессии
+func (i *Interviewer) SetSessionTTL(ttl time.Duration) {
+	i.sessionTTL.Store(int64(ttl))
+}
+
+// activeSession возвращает сессию, если она есть и еще не истекла.
+// Истекшая сессия удаляется сразу, не дожидаясь очистки по расписанию.
+// Вызывающий должен держать блокировку пользователя.
+func (i *Interviewer) activeSession(userID int64) *models.InterviewSession {
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil
+	}
+	if i.isExpired(session, time.Now()) {
+		i.sessions.DeleteSession(userID)
+		return nil
+	}
+	return session
+}
+
+func (i *Interviewer) isExpired(session *models.InterviewSession, now time.Time) bool {
+	ttl := time.Duration(i.sessionTTL.Load())
+	return ttl > 0 && now.Sub(session.LastActivity()) > ttl
+}
+
+// Sweep удаляет истекшие сессии и отбирает те, по которым пора напомнить
+// пользователю (неактивны дольше remindAfter и напоминание еще не отправлялось).
+// remindAfter == 0 отключает напоминания. Возвращаются копии сессий.
+func (i *Interviewer) Sweep(now time.Time, remindAfter time.Duration) (remind, expired []*models.InterviewSession) {
+	for _, listed := range i.sessions.ListSessions() {
+		session, result := i.sweepSession(listed.UserID, now, remindAfter)
+		switch result {
+		case sweepRemind:
+			remind = append(remind, session)
+		case sweepExpired:
+			expired = append(expired, session)
+		}
+	}
+
+	return remind, expired
+}
+
+type sweepResult int
+
+const (
+	sweepNone sweepResult = iota
+	sweepRemind
+	sweepExpired
+)
+
+// sweepSession проверяет одну сессию под блокировкой ее пользователя
+func (i *Interviewer) sweepSession(userID int64, now time.Time, remindAfter time.Duration) (*models.InterviewSession, sweepResult) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil, sweepNone
+	}
+
+	if i.isExpired(session, now) {
+		if err := i.sessions.DeleteSession(userID); err != nil {
+			return nil, sweepNone
+		}
+		return session.Clone(), sweepExpired
+	}
+
+	if remindAfter <= 0 || now.Sub(session.LastActivity()) < remindAfter {
+		return nil, sweepNone
+	}
+	if session.RemindedAt.After(session.LastActivity()) {
+		return nil, sweepNone
+	}
+
+	session.RemindedAt = now
+	if err := i.sessions.SaveSession(session); err != nil {
+		return nil, sweepNone
+	}
+	return session.Clone(), sweepRemind
+}
+
+// Progress возвращает номер текущего вопроса (с единицы) и ожидаемое число вопросов.
+// Ожидаемое число зависит от ответов и может меняться по ходу интервью.
+func (i *Interviewer) Progress(session *models.InterviewSession) (int, int) {
+	return i.progress(session)
+}
+
+func (i *Interviewer) progress(session *models.InterviewSession) (int, int) {
+	if session.Mode == ModeChat {
+		return session.CurrentStep + 1, i.chatLimits().MaxTurns
+	}
+	node := i.currentNode(session)
+	return session.CurrentStep + 1, session.CurrentStep + i.bank().Remaining(session.Type, node, session.Context)
+}
@@ -0,0 +1,52 @@
+package vibot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSweepRemindsOnceThenExpires(t *testing.T) {
+	i := newTestInterviewer(t, "{}")
+	i.SetSessionTTL(72 * time.Hour)
+	const userID = 7
+
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	started := time.Now()
+
+	if remind, expired := i.Sweep(started.Add(time.Hour), 24*time.Hour); len(remind)+len(expired) != 0 {
+		t.Fatalf("fresh session: remind %d, expired %d; want nothing", len(remind), len(expired))
+	}
+
+	remind, expired := i.Sweep(started.Add(25*time.Hour), 24*time.Hour)
+	if len(remind) != 1 || remind[0].UserID != userID || len(expired) != 0 {
+		t.Fatalf("after 25h: remind %v, expired %v; want one reminder for %d", remind, expired, userID)
+	}
+	if remind, _ := i.Sweep(started.Add(26*time.Hour), 24*time.Hour); len(remind) != 0 {
+		t.Errorf("reminded twice about the same inactivity")
+	}
+	if i.GetInterviewType(userID) != "task" {
+		t.Fatal("reminder removed the session")
+	}
+
+	remind, expired = i.Sweep(started.Add(73*time.Hour), 24*time.Hour)
+	if len(expired) != 1 || expired[0].UserID != userID || len(remind) != 0 {
+		t.Fatalf("after TTL: remind %v, expired %v; want the session expired", remind, expired)
+	}
+	if i.GetInterviewType(userID) != "" {
+		t.Error("expired session is still stored")
+	}
+}
+
+func TestSweepWithoutTTLOrReminders(t *testing.T) {
+	i := newTestInterviewer(t, "{}")
+	if err := i.StartInterview(7, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+
+	remind, expired := i.Sweep(time.Now().Add(365*24*time.Hour), 0)
+	if len(remind)+len(expired) != 0 {
+		t.Errorf("remind %d, expired %d; want nothing with TTL and reminders off", len(remind), len(expired))
+	}
+}
@@ -0,0 +1,72 @@
+package vibot
+
+import "viget-mvp/internal/models"
+
+// currentNode возвращает вопрос на текущей позиции или EndNode
+func (i *Interviewer) currentNode(session *models.InterviewSession) string {
+	i.ensureHistory(session)
+	if session.CurrentStep < 0 || session.CurrentStep >= len(session.History) {
+		return EndNode
+	}
+	return session.History[session.CurrentStep]
+}
+
+func (i *Interviewer) currentTemplate(session *models.InterviewSession) (QuestionTemplate, bool) {
+	return i.bank().GetTemplate(session.Type, i.currentNode(session))
+}
+
+// ensureHistory восстанавливает путь для сессий, сохраненных до появления графа:
+// тогда интервью шло по вопросам строго по порядку
+func (i *Interviewer) ensureHistory(session *models.InterviewSession) {
+	if len(session.History) > 0 || session.Mode == ModeChat {
+		return
+	}
+
+	node := i.bank().First(session.Type)
+	for pos := 0; pos <= session.CurrentStep && node != EndNode; pos++ {
+		session.History = append(session.History, node)
+		node = i.bank().Next(session.Type, node, nil, nil)
+	}
+}
+
+// advance переходит от позиции pos дальше по графу. Если путь совпадает с уже
+// пройденным (после правки ответа), сохраненные ответы переиспользуются;
+// при расхождении хвост пути и его ответы отбрасываются.
+// Возвращает true, если интервью окончено.
+func (i *Interviewer) advance(session *models.InterviewSession, pos int) bool {
+	for {
+		key := answerKey(pos)
+		next := i.bank().Next(session.Type, session.History[pos], session.Parsed[key], session.Context)
+
+		if next != EndNode && pos+1 < len(session.History) && session.History[pos+1] == next {
+			if _, answered := session.Answers[answerKey(pos+1)]; answered {
+				pos++
+				continue
+			}
+		}
+
+		i.truncate(session, pos+1)
+		if next == EndNode {
+			session.CurrentStep = len(session.History)
+			return true
+		}
+		session.History = append(session.History, next)
+		session.CurrentStep = pos + 1
+		return false
+	}
+}
+
+// truncate отбрасывает позиции начиная с length вместе с ответами
+func (i *Interviewer) truncate(session *models.InterviewSession, length int) {
+	if length >= len(session.History) {
+		return
+	}
+	for pos := length; pos < len(session.History); pos++ {
+		key := answerKey(pos)
+		delete(session.Answers, key)
+		delete(session.Parsed, key)
+		delete(session.StepContext, key)
+	}
+	session.History = session.History[:length]
+	i.rebuildContext(session)
+}
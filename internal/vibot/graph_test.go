@@ -0,0 +1,111 @@
+package vibot
+
+import (
+	"reflect"
+	"testing"
+
+	"viget-mvp/pkg/gpt"
+)
+
+// history возвращает пройденный путь по вопросам
+func history(t *testing.T, i *Interviewer, userID int64) []string {
+	t.Helper()
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		t.Fatal("session not found")
+	}
+	return session.History
+}
+
+func TestMoreSkillsLoopsBackToSkills(t *testing.T) {
+	i := newDefaultInterviewer()
+	const userID = 7
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+
+	answerAll(t, i, userID, "Лендинг", "Сделать лендинг для стартапа", "HTML - 3", "Да")
+	if got := currentID(t, i, userID); got != "skills" {
+		t.Fatalf("after «Да» current question = %q, want skills again", got)
+	}
+
+	answerAll(t, i, userID, "CSS - 2", "Нет")
+	if got := currentID(t, i, userID); got != "budget" {
+		t.Fatalf("after «Нет» current question = %q, want budget", got)
+	}
+
+	want := []string{"title", "description", "skills", "more_skills", "skills", "more_skills", "budget"}
+	if got := history(t, i, userID); !reflect.DeepEqual(got, want) {
+		t.Errorf("history = %v, want %v", got, want)
+	}
+}
+
+func TestNoRequirementsEndsTask(t *testing.T) {
+	i := newDefaultInterviewer()
+	const userID = 7
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+
+	finished := answerAll(t, i, userID, "Лендинг", "Сделать лендинг для стартапа", "HTML - 3", "Нет", "30000", "14 дней", "Нет")
+	if !finished {
+		t.Fatalf("interview continues at %q, want it finished after has_requirements: no", currentID(t, i, userID))
+	}
+	if got := history(t, i, userID); got[len(got)-1] != "has_requirements" {
+		t.Errorf("history = %v, want it to end at has_requirements", got)
+	}
+}
+
+func TestRequirementsAskedWhenPresent(t *testing.T) {
+	i := newDefaultInterviewer()
+	const userID = 7
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+
+	if answerAll(t, i, userID, "Лендинг", "Сделать лендинг для стартапа", "HTML - 3", "Нет", "30000", "14 дней", "Да") {
+		t.Fatal("interview finished without asking for the requirements")
+	}
+	if got := currentID(t, i, userID); got != "requirements" {
+		t.Errorf("current question = %q, want requirements", got)
+	}
+}
+
+func TestNoExperienceSkipsLevel(t *testing.T) {
+	i := newDefaultInterviewer(gpt.FakeRule{
+		Contains: []string{"Не программирую"},
+		Response: `{"experience_level": "none"}`,
+	})
+	const userID = 7
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+
+	answerAll(t, i, userID, "Анна", "Не программирую, занимаюсь дизайном")
+	if got := currentID(t, i, userID); got != "interests" {
+		t.Fatalf("current question = %q, want interests without the level question", got)
+	}
+
+	// С опытом вопрос об уровне задается
+	if _, err := i.EditAnswer(userID, 1); err != nil {
+		t.Fatalf("EditAnswer: %v", err)
+	}
+	answerAll(t, i, userID, "Пишу на Go три года")
+	if got := currentID(t, i, userID); got != "level" {
+		t.Errorf("after editing the experience current question = %q, want level", got)
+	}
+}
+
+func TestSessionContextFromChoices(t *testing.T) {
+	i := newDefaultInterviewer()
+	const userID = 7
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	answerAll(t, i, userID, "Анна", "Go", "4")
+
+	session := i.sessions.GetSession(userID)
+	if got := session.Context["experience_level"]; got != "senior" {
+		t.Errorf("experience_level = %v, want senior from the chosen option", got)
+	}
+}
@@ -2,41 +2,85 @@
 package vibot
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"log"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
+	"viget-mvp/internal/extractor"
 	"viget-mvp/internal/models"
+	"viget-mvp/internal/semantic"
+	"viget-mvp/internal/skills"
 	"viget-mvp/pkg/gpt"
 )
 
+// llmTimeout ограничивает обращение к модели вместе со всеми повторами
+const llmTimeout = 2 * time.Minute
+
+// Interviewer ведет интервью. Сессии разных пользователей обрабатываются
+// параллельно под блокировкой своего пользователя; обращения к модели идут
+// без блокировок, а результат применяется, только если сессия за это время
+// не изменилась.
 type Interviewer struct {
-	gptClient *gpt.Client
-	sessions  map[int64]*models.InterviewSession
-	mutex     sync.RWMutex
-	questions *QuestionBank
+	extractor *extractor.Extractor
+	sessions  SessionStore
+	locks     *userLocks
+	// questions — банк вопросов; заменяется целиком (SetQuestionBank),
+	// поэтому хранится в atomic.Pointer
+	questions atomic.Pointer[QuestionBank]
+
+	// sessionTTL — через сколько неактивности сессия считается брошенной (0 — никогда)
+	sessionTTL atomic.Int64
+	// chat — ограничения беседы, если интервью профиля ведет модель (nil — анкета)
+	chat atomic.Pointer[ChatLimits]
+	// taxonomy приводит навыки к каноническим (nil — как их назвала модель)
+	taxonomy atomic.Pointer[skills.Taxonomy]
+	// index считает векторы интересов и задач (nil — их дополнит подбор)
+	index atomic.Pointer[semantic.Index]
 }
 
-func NewInterviewer(gptClient *gpt.Client) *Interviewer {
-	return &Interviewer{
-		gptClient: gptClient,
-		sessions:  make(map[int64]*models.InterviewSession),
-		questions: NewQuestionBank(),
+// ErrSessionChanged — интервью изменилось (отменено, начато заново, отредактировано),
+// пока модель обрабатывала ответы
+var ErrSessionChanged = errors.New("interview session changed")
+
+// ErrNotSaved — результат интервью не удалось сохранить; сессия осталась,
+// и извлечение можно повторить
+var ErrNotSaved = errors.New("interview result not saved")
+
+func NewInterviewer(ext *extractor.Extractor, sessions SessionStore) *Interviewer {
+	if sessions == nil {
+		sessions = NewSessionStorage()
+	}
+	i := &Interviewer{
+		extractor: ext,
+		sessions:  sessions,
+		locks:     newUserLocks(),
 	}
+	i.questions.Store(NewQuestionBank())
+	return i
 }
 
-func (i *Interviewer) StartInterview(userID int64, interviewType string) error {
-	i.mutex.Lock()
-	defer i.mutex.Unlock()
+// SetQuestionBank заменяет встроенный банк вопросов, например загруженным из файла
+func (i *Interviewer) SetQuestionBank(bank *QuestionBank) {
+	i.questions.Store(bank)
+}
+
+func (i *Interviewer) bank() *QuestionBank {
+	return i.questions.Load()
+}
 
+func (i *Interviewer) StartInterview(userID int64, interviewType string) error {
 	// Проверяем валидность типа интервью
 	if interviewType != "profile" && interviewType != "task" {
 		return fmt.Errorf("invalid interview type: %s", interviewType)
 	}
 
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
 	session := &models.InterviewSession{
 		UserID:      userID,
 		Type:        interviewType,
@@ -44,369 +88,434 @@ func (i *Interviewer) StartInterview(userID int64, interviewType string) error {
 		Answers:     make(map[string]interface{}),
 		Context:     make(map[string]interface{}),
 		StartedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		History:     []string{i.bank().First(interviewType)},
+	}
+	if interviewType == "profile" && i.chat.Load() != nil {
+		session.Mode = ModeChat
+		session.History = nil
+		session.Transcript = []models.ChatMessage{{Role: gpt.RoleAssistant, Content: chatOpening}}
 	}
 
-	i.sessions[userID] = session
-	return nil
+	return i.sessions.SaveSession(session)
 }
 
 func (i *Interviewer) GetCurrentQuestion(userID int64) string {
-	i.mutex.RLock()
-	defer i.mutex.RUnlock()
+	unlock := i.locks.lock(userID)
+	defer unlock()
 
-	session, exists := i.sessions[userID]
-	if !exists {
+	session := i.sessions.GetSession(userID)
+	if session == nil {
 		return "❌ Интервью не найдено. Используйте /interview для создания профиля или /create_task для создания задачи."
 	}
 
-	question := i.questions.GetQuestion(session.Type, session.CurrentStep, session.Context)
+	return i.formatQuestion(session)
+}
+
+func (i *Interviewer) formatQuestion(session *models.InterviewSession) string {
+	if session.Mode == ModeChat {
+		return i.formatChat(session)
+	}
+
+	question := i.bank().GetQuestion(session.Type, i.currentNode(session), session.Context)
+	current, total := i.progress(session)
 
 	// Добавляем префикс в зависимости от типа интервью
 	var prefix string
 	switch session.Type {
 	case "profile":
-		prefix = fmt.Sprintf("👤 Создание профиля (вопрос %d/%d)\n\n",
-			session.CurrentStep+1, i.questions.GetMaxSteps(session.Type))
+		prefix = fmt.Sprintf("👤 Создание профиля (вопрос %d/%d)\n\n", current, total)
 	case "task":
-		prefix = fmt.Sprintf("📋 Создание задачи (вопрос %d/%d)\n\n",
-			session.CurrentStep+1, i.questions.GetMaxSteps(session.Type))
+		prefix = fmt.Sprintf("📋 Создание задачи (вопрос %d/%d)\n\n", current, total)
 	}
 
 	return prefix + question
 }
 
-func (i *Interviewer) ProcessAnswer(userID int64, answer string) (string, bool, error) {
-	i.mutex.Lock()
-	defer i.mutex.Unlock()
+// ProcessAnswer отвечает на текущий вопрос. В беседе progress получает
+// следующую реплику интервьюера по мере того, как модель ее пишет.
+func (i *Interviewer) ProcessAnswer(userID int64, answer string, progress gpt.ProgressFunc) (string, bool, error) {
+	if i.isChat(userID) {
+		return i.chatAnswer(userID, answer, progress)
+	}
+	return i.answer(userID, func(*models.InterviewSession, QuestionTemplate) (string, interface{}, error) {
+		return answer, nil, nil
+	})
+}
 
-	session, exists := i.sessions[userID]
-	if !exists {
-		return "", false, fmt.Errorf("session not found")
+// pendingAnswer — проверенный ответ, ожидающий анализа моделью
+type pendingAnswer struct {
+	userID int64
+	revision
+	answer      string
+	parsed      interface{}
+	stepContext map[string]interface{}
+	// analyze — ответ нужно разобрать моделью для контекста
+	analyze bool
+}
+
+// revision определяет состояние сессии: если оно поменялось, пока шло
+// обращение к модели, результат уже не относится к текущему вопросу
+type revision struct {
+	interviewType string
+	step          int
+	startedAt     time.Time
+	updatedAt     time.Time
+}
+
+func revisionOf(session *models.InterviewSession) revision {
+	return revision{
+		interviewType: session.Type,
+		step:          session.CurrentStep,
+		startedAt:     session.StartedAt,
+		updatedAt:     session.UpdatedAt,
+	}
+}
+
+// key — идентификатор сессии для выбора версии промпта в эксперименте
+func (r revision) key(userID int64) string {
+	return fmt.Sprintf("%d:%d", userID, r.startedAt.UnixNano())
+}
+
+func (r revision) matches(session *models.InterviewSession) bool {
+	return session != nil && session.Type == r.interviewType && session.CurrentStep == r.step &&
+		session.StartedAt.Equal(r.startedAt) && session.UpdatedAt.Equal(r.updatedAt)
+}
+
+// resolveFunc получает сессию и шаблон вопроса и возвращает текст ответа.
+// Ответ кнопками возвращает и разобранное значение: варианты выбраны по
+// номерам, и разбирать их подписи из текста не нужно.
+type resolveFunc func(*models.InterviewSession, QuestionTemplate) (answer string, parsed interface{}, err error)
+
+// answer отвечает на текущий вопрос ответом, который вернет resolve
+func (i *Interviewer) answer(userID int64, resolve resolveFunc) (string, bool, error) {
+	pending, reply, finished, err := i.prepareAnswer(userID, resolve)
+	if pending == nil {
+		return reply, finished, err
 	}
 
-	// Валидация ответа
-	if strings.TrimSpace(answer) == "" {
-		return "⚠️ Пожалуйста, дайте ответ на вопрос.", false, nil
+	if pending.analyze {
+		// Анализируем ответ с помощью GPT для контекста — без блокировки
+		context, err := i.analyzeAnswer(userID, pending.key(userID), pending.interviewType, pending.answer)
+		if err != nil {
+			// Без анализа вопросы просто не адаптируются — интервью продолжается
+			log.Printf("analyze answer for user %d: %v", userID, err)
+		}
+		pending.stepContext = context
 	}
 
-	// Сохраняем ответ
-	questionKey := fmt.Sprintf("q_%d", session.CurrentStep)
-	session.Answers[questionKey] = answer
+	return i.commitAnswer(pending)
+}
+
+// prepareAnswer проверяет ответ по типу вопроса. Если ответ не прошел проверку,
+// pending == nil, а reply содержит подсказку и повтор вопроса.
+func (i *Interviewer) prepareAnswer(userID int64, resolve resolveFunc) (pending *pendingAnswer, reply string, finished bool, err error) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil, "", false, fmt.Errorf("session not found")
+	}
+	if session.Mode == ModeChat {
+		return nil, "", false, fmt.Errorf("chat interview has no questions to choose from")
+	}
 
-	// Анализируем ответ с помощью GPT для контекста
-	context, err := i.analyzeAnswer(answer, session)
-	if err == nil {
-		for k, v := range context {
-			session.Context[k] = v
+	// Валидация и разбор ответа по типу вопроса
+	template, ok := i.currentTemplate(session)
+	if !ok {
+		return nil, "", true, nil
+	}
+	answer, parsed, err := resolve(session, template)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if parsed == nil {
+		parsed, err = ParseAnswer(template, answer, time.Now())
+	}
+	if err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			return nil, "⚠️ " + verr.Message + "\n\n" + i.formatQuestion(session), false, nil
 		}
+		return nil, "", false, err
+	}
+
+	pending = &pendingAnswer{
+		userID:   userID,
+		revision: revisionOf(session),
+		answer:   strings.TrimSpace(answer),
+		parsed:   parsed,
+	}
+	switch {
+	case parsed == nil:
+	case template.Type == "choice":
+		// Структурированный ответ не требует интерпретации моделью
+		pending.stepContext = choiceContext(template, parsed)
+	case template.Type == "text":
+		// Числа, суммы и сроки уже разобраны — модель к ним ничего не добавит
+		pending.analyze = true
+	}
+	return pending, "", false, nil
+}
+
+// commitAnswer сохраняет ответ и переходит к следующему вопросу
+func (i *Interviewer) commitAnswer(pending *pendingAnswer) (string, bool, error) {
+	unlock := i.locks.lock(pending.userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(pending.userID)
+	if session == nil {
+		return "", false, fmt.Errorf("session not found")
+	}
+	if !pending.matches(session) {
+		return "⚠️ Пока обрабатывался ответ, интервью изменилось. Текущий вопрос:\n\n" + i.formatQuestion(session), false, nil
+	}
+
+	// Сохраняем ответ (при редактировании перезаписываем старый)
+	questionKey := answerKey(session.CurrentStep)
+	session.Answers[questionKey] = pending.answer
+	if session.Parsed == nil {
+		session.Parsed = make(map[string]interface{})
+	}
+	if pending.parsed != nil {
+		session.Parsed[questionKey] = pending.parsed
+	} else {
+		delete(session.Parsed, questionKey)
+	}
+
+	// Контекст хранится по шагам, чтобы правка ответа заменяла только его вклад
+	if session.StepContext == nil {
+		session.StepContext = make(map[string]map[string]interface{})
 	}
+	delete(session.StepContext, questionKey)
+	if pending.stepContext != nil {
+		session.StepContext[questionKey] = pending.stepContext
+	}
+	i.rebuildContext(session)
+	session.Selected = nil
+
+	// Переходим по графу к следующему вопросу. После правки ответа,
+	// если путь не изменился, пользователь возвращается туда, где остановился
+	finished := i.advance(session, session.CurrentStep)
+	session.UpdatedAt = time.Now()
 
-	// Переходим к следующему шагу
-	session.CurrentStep++
+	// Сохраняем прогресс до ответа пользователю, чтобы пережить перезапуск
+	if err := i.sessions.SaveSession(session); err != nil {
+		return "", false, err
+	}
 
 	// Проверяем, завершено ли интервью
-	maxSteps := i.questions.GetMaxSteps(session.Type)
-	if session.CurrentStep >= maxSteps {
+	if finished {
 		return "", true, nil
 	}
 
 	// Возвращаем следующий вопрос
-	nextQuestion := i.GetCurrentQuestion(userID)
+	nextQuestion := i.formatQuestion(session)
 	return nextQuestion, false, nil
 }
 
-func (i *Interviewer) ExtractProfile(userID int64) (*models.UserProfile, error) {
-	i.mutex.Lock()
-	defer i.mutex.Unlock()
+// SetTaxonomy включает приведение навыков к каноническим при извлечении
+func (i *Interviewer) SetTaxonomy(taxonomy *skills.Taxonomy) {
+	i.taxonomy.Store(taxonomy)
+}
 
-	session, exists := i.sessions[userID]
-	if !exists {
-		return nil, fmt.Errorf("session not found")
-	}
+// SetIndex включает расчет векторов интересов и задач при извлечении
+func (i *Interviewer) SetIndex(index *semantic.Index) {
+	i.index.Store(index)
+}
 
-	if session.Type != "profile" {
-		return nil, fmt.Errorf("not a profile interview session")
+// ExtractProfile извлекает профиль из завершенного интервью, сохраняет его
+// через save и только после этого удаляет сессию. progress получает ответ
+// модели (JSON) по мере генерации.
+func (i *Interviewer) ExtractProfile(userID int64, progress gpt.ProgressFunc, save func(*models.UserProfile) error) (*models.UserProfile, error) {
+	snapshot, err := i.snapshot(userID, "profile")
+	if err != nil {
+		return nil, err
 	}
 
-	// Собираем все ответы в один текст
-	var allAnswers string
-	for j := 0; j < session.CurrentStep; j++ {
-		questionKey := fmt.Sprintf("q_%d", j)
-		if answer, ok := session.Answers[questionKey]; ok {
-			allAnswers += fmt.Sprintf("Вопрос %d: %v\n\n", j+1, answer)
-		}
-	}
+	// Извлекаем структурированные данные через GPT — без блокировки
+	ctx, cancel := context.WithTimeout(gpt.WithProgress(gpt.WithUser(context.Background(), userID), progress), llmTimeout)
+	defer cancel()
 
-	// Извлекаем структурированные данные через GPT
-	extractedData, err := i.extractStructuredData(allAnswers, session.Type)
+	data, err := i.extractor.ExtractProfile(ctx, snapshot.key(userID), snapshot.answers)
 	if err != nil {
 		return nil, err
 	}
-
-	// Создаем профиль
-	profile := &models.UserProfile{
-		ID:         strconv.FormatInt(userID, 10),
-		TelegramID: userID,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-		Skills:     make(map[string]models.SkillLevel),
-		Verified:   make(map[string]bool),
-	}
-
-	// Заполняем данные из извлеченной информации
-	if name, ok := extractedData["name"].(string); ok {
-		profile.Name = name
-	}
-
-	if skills, ok := extractedData["skills"].(map[string]interface{}); ok {
-		for skillName, levelData := range skills {
-			if skillInfo, ok := levelData.(map[string]interface{}); ok {
-				level := 1
-				if l, ok := skillInfo["level"].(float64); ok {
-					level = int(l)
-				}
-				profile.Skills[skillName] = models.SkillLevel{
-					Name:     skillName,
-					Level:    level,
-					Verified: false,
-					Source:   "interview",
-				}
-			}
-		}
+	profile := data.ToUserProfile(userID, time.Now())
+	// Разобранная сумма точнее того, что вернула модель
+	if budget, ok := parsedInt(snapshot.parsed["currency"]); ok {
+		profile.MinBudget = budget
 	}
-
-	if interests, ok := extractedData["interests"].([]interface{}); ok {
-		for _, interest := range interests {
-			if str, ok := interest.(string); ok {
-				profile.Interests = append(profile.Interests, str)
-			}
-		}
+	if taxonomy := i.taxonomy.Load(); taxonomy != nil {
+		taxonomy.NormalizeProfile(profile)
 	}
-
-	if goals, ok := extractedData["goals"].([]interface{}); ok {
-		for _, goal := range goals {
-			if str, ok := goal.(string); ok {
-				profile.Goals = append(profile.Goals, str)
-			}
+	if index := i.index.Load(); index != nil {
+		// Без векторов профиль все равно полезен: подбор посчитает их позже
+		if err := index.EmbedProfile(ctx, profile); err != nil {
+			log.Printf("embed profile %d: %v", userID, err)
 		}
 	}
 
-	if softSkills, ok := extractedData["soft_skills"].([]interface{}); ok {
-		for _, skill := range softSkills {
-			if str, ok := skill.(string); ok {
-				profile.SoftSkills = append(profile.SoftSkills, str)
-			}
-		}
+	if err := i.finish(userID, snapshot.revision, func() error { return save(profile) }); err != nil {
+		return nil, err
 	}
-
-	// Удаляем сессию
-	delete(i.sessions, userID)
 	return profile, nil
 }
 
-func (i *Interviewer) ExtractTask(userID int64) (*models.TaskProfile, error) {
-	i.mutex.Lock()
-	defer i.mutex.Unlock()
-
-	session, exists := i.sessions[userID]
-	if !exists {
-		return nil, fmt.Errorf("session not found")
+// ExtractTask извлекает задачу из завершенного интервью, сохраняет ее через
+// save и только после этого удаляет сессию
+func (i *Interviewer) ExtractTask(userID int64, progress gpt.ProgressFunc, save func(*models.TaskProfile) error) (*models.TaskProfile, error) {
+	snapshot, err := i.snapshot(userID, "task")
+	if err != nil {
+		return nil, err
 	}
 
-	// Собираем все ответы в один текст
-	var allAnswers string
-	for j := 0; j < session.CurrentStep; j++ {
-		questionKey := fmt.Sprintf("q_%d", j)
-		if answer, ok := session.Answers[questionKey]; ok {
-			question := i.questions.GetQuestion(session.Type, j, session.Context)
-			allAnswers += fmt.Sprintf("Q: %s\nA: %v\n\n", question, answer)
-		}
-	}
+	ctx, cancel := context.WithTimeout(gpt.WithProgress(gpt.WithUser(context.Background(), userID), progress), llmTimeout)
+	defer cancel()
 
-	extractedData, err := i.extractStructuredData(allAnswers, session.Type)
+	data, err := i.extractor.ExtractTask(ctx, snapshot.key(userID), snapshot.answers)
 	if err != nil {
 		return nil, err
 	}
-
-	if session.Type == "task" {
-		task := &models.TaskProfile{
-			ID:        fmt.Sprintf("task_%d", userID),
-			CreatedBy: fmt.Sprintf("user_%d", userID),
-			Status:    "open",
-			CreatedAt: session.StartedAt,
+	task := data.ToTaskProfile(userID, snapshot.startedAt)
+	if taxonomy := i.taxonomy.Load(); taxonomy != nil {
+		taxonomy.NormalizeTask(task)
+	}
+	if index := i.index.Load(); index != nil {
+		if err := index.EmbedTask(ctx, task); err != nil {
+			log.Printf("embed task %d: %v", userID, err)
 		}
+	}
 
-		if title, ok := extractedData["title"].(string); ok {
-			task.Title = title
-		}
-		if desc, ok := extractedData["description"].(string); ok {
-			task.Description = desc
-		}
-		if skills, ok := extractedData["required_skills"].(map[string]interface{}); ok {
-			task.RequiredSkills = make(map[string]int)
-			for skill, level := range skills {
-				if lvl, ok := level.(float64); ok {
-					task.RequiredSkills[skill] = int(lvl)
-				}
-			}
-		}
-		if budget, ok := extractedData["budget"].(float64); ok {
-			task.Budget = int(budget)
-		}
-		if deadlineDays, ok := extractedData["deadline_days"].(float64); ok {
-			task.Deadline = session.StartedAt.AddDate(0, 0, int(deadlineDays))
+	// Разобранные ответы точнее того, что вернула модель
+	if budget, ok := parsedInt(snapshot.parsed["currency"]); ok {
+		task.Budget = budget
+	}
+	if date, ok := snapshot.parsed["deadline"].(string); ok {
+		if deadline, err := time.ParseInLocation(deadlineFormat, date, time.Local); err == nil {
+			task.Deadline = deadline
 		}
-
-		delete(i.sessions, userID)
-		return task, nil
 	}
 
-	return nil, fmt.Errorf("unsupported interview type: %s", session.Type)
+	if err := i.finish(userID, snapshot.revision, func() error { return save(task) }); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
-func (i *Interviewer) analyzeAnswer(answer string, session *models.InterviewSession) (map[string]interface{}, error) {
-	var prompt string
-
-	if session.Type == "profile" {
-		prompt = fmt.Sprintf(`Проанализируй ответ пользователя на интервью для создания профиля и извлеки ключевую информацию.
+// sessionSnapshot — ответы завершенного интервью для извлечения без блокировки
+type sessionSnapshot struct {
+	revision
+	answers []extractor.Answer
+	// parsed — разобранный ответ на первый пройденный вопрос каждого типа
+	parsed map[string]interface{}
+}
 
-Ответ: "%s"
+func (i *Interviewer) snapshot(userID int64, interviewType string) (*sessionSnapshot, error) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
 
-Определи:
-1. Основные навыки или технологии, упомянутые в ответе
-2. Уровень опыта (junior/middle/senior)
-3. Интересы и предпочтения
-4. Любую другую важную информацию для профиля
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found")
+	}
+	if session.Type != interviewType {
+		return nil, fmt.Errorf("not a %s interview session", interviewType)
+	}
 
-Верни в JSON формате:
-{
-  "mentioned_skills": ["skill1", "skill2"],
-  "experience_level": "junior|middle|senior",
-  "interests": ["interest1"],
-  "key_info": "краткое резюме"
-}`, answer)
-	} else if session.Type == "task" {
-		prompt = fmt.Sprintf(`Проанализируй ответ пользователя на интервью для создания задачи и извлеки ключевую информацию.
+	return &sessionSnapshot{
+		revision: revisionOf(session),
+		answers:  i.interviewAnswers(session),
+		parsed: map[string]interface{}{
+			"currency": i.parsedByType(session, "currency"),
+			"deadline": i.parsedByType(session, "deadline"),
+		},
+	}, nil
+}
 
-Ответ: "%s"
+// finish сохраняет результат и удаляет сессию, если она не менялась с момента
+// снимка. Если сохранить не удалось, сессия остается — ответы не теряются.
+func (i *Interviewer) finish(userID int64, rev revision, save func() error) error {
+	unlock := i.locks.lock(userID)
+	defer unlock()
 
-Определи:
-1. Упомянутые технологии или требования
-2. Сложность задачи (simple/medium/complex)
-3. Тип проекта
-4. Любую другую важную информацию для задачи
+	if !rev.matches(i.sessions.GetSession(userID)) {
+		return ErrSessionChanged
+	}
+	if err := save(); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotSaved, err)
+	}
+	return i.sessions.DeleteSession(userID)
+}
 
-Верни в JSON формате:
-{
-  "mentioned_technologies": ["tech1", "tech2"],
-  "task_complexity": "simple|medium|complex",
-  "project_type": "web|mobile|data|design|other",
-  "key_info": "краткое резюме"
-}`, answer)
+// parsedInt читает разобранное число; из сохраненной в JSON сессии оно возвращается как float64
+func parsedInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
 	}
+	return 0, false
+}
 
-	response, err := i.gptClient.SendRequest(prompt)
-	if err != nil {
-		return nil, err
+// parsedByType возвращает разобранный ответ на первый пройденный вопрос указанного типа
+func (i *Interviewer) parsedByType(session *models.InterviewSession, questionType string) interface{} {
+	for pos, node := range session.History {
+		if q, ok := i.bank().GetTemplate(session.Type, node); ok && q.Type == questionType {
+			return session.Parsed[answerKey(pos)]
+		}
 	}
+	return nil
+}
 
-	var context map[string]interface{}
-	err = json.Unmarshal([]byte(response), &context)
-	return context, err
+func (i *Interviewer) analyzeAnswer(userID int64, sessionKey, interviewType, answer string) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(gpt.WithUser(context.Background(), userID), llmTimeout)
+	defer cancel()
+
+	return i.extractor.AnalyzeAnswer(ctx, sessionKey, interviewType, answer)
 }
 
-func (i *Interviewer) extractStructuredData(answers string, sessionType string) (map[string]interface{}, error) {
-	var prompt string
-
-	if sessionType == "profile" {
-		prompt = fmt.Sprintf(`Проанализируй интервью с пользователем для создания профиля и извлеки структурированную информацию.
-
-Ответы на интервью:
-%s
-
-Извлеки и структурируй следующую информацию:
-1. Имя пользователя
-2. Технические навыки с уровнем (1-5)
-3. Soft skills
-4. Интересы и хобби
-5. Профессиональные цели
-6. Опыт работы
-
-Верни в JSON формате:
-{
-  "name": "Имя",
-  "skills": {
-    "Python": {"level": 3, "confidence": 0.8},
-    "JavaScript": {"level": 2, "confidence": 0.6}
-  },
-  "soft_skills": ["коммуникация", "командная работа"],
-  "interests": ["машинное обучение", "веб-разработка"],
-  "goals": ["стать senior разработчиком", "изучить Go"],
-  "experience": [
-    {
-      "company": "ООО Пример",
-      "position": "Junior Developer", 
-      "duration": "6 месяцев",
-      "skills": ["Python", "Django"]
-    }
-  ]
-}`, answers)
-	} else if sessionType == "task" {
-		prompt = fmt.Sprintf(`Проанализируй интервью с пользователем для создания задачи и извлеки требования.
-
-Ответы на интервью:
-%s
-
-Извлеки:
-1. Название задачи
-2. Подробное описание
-3. Требуемые навыки с минимальным уровнем (1-5)
-4. Бюджет
-5. Сроки выполнения в днях
-
-Верни в JSON формате:
-{
-  "title": "Название задачи",
-  "description": "Подробное описание что нужно сделать",
-  "required_skills": {
-    "Python": 3,
-    "React": 2,
-    "CSS": 2
-  },
-  "budget": 50000,
-  "deadline_days": 14
-}`, answers)
-	}
-
-	response, err := i.gptClient.SendRequest(prompt)
-	if err != nil {
-		return nil, err
+// interviewAnswers собирает пройденные вопросы с ответами для извлечения
+func (i *Interviewer) interviewAnswers(session *models.InterviewSession) []extractor.Answer {
+	if session.Mode == ModeChat {
+		return chatAnswers(session.Transcript)
 	}
+	i.ensureHistory(session)
 
-	var result map[string]interface{}
-	err = json.Unmarshal([]byte(response), &result)
-	return result, err
+	var answers []extractor.Answer
+	for j, node := range session.History {
+		answer, ok := session.Answers[answerKey(j)]
+		if !ok {
+			continue
+		}
+		answers = append(answers, extractor.Answer{
+			Question: i.bank().GetQuestion(session.Type, node, session.Context),
+			Answer:   fmt.Sprint(answer),
+		})
+	}
+	return answers
 }
 
 func (i *Interviewer) IsInInterview(userID int64) bool {
-	i.mutex.RLock()
-	defer i.mutex.RUnlock()
-	_, exists := i.sessions[userID]
-	return exists
+	unlock := i.locks.lock(userID)
+	defer unlock()
+	return i.activeSession(userID) != nil
 }
 
 func (i *Interviewer) GetInterviewType(userID int64) string {
-	i.mutex.RLock()
-	defer i.mutex.RUnlock()
+	unlock := i.locks.lock(userID)
+	defer unlock()
 
-	if session, exists := i.sessions[userID]; exists {
+	if session := i.activeSession(userID); session != nil {
 		return session.Type
 	}
 	return ""
 }
 
 func (i *Interviewer) CancelInterview(userID int64) {
-	i.mutex.Lock()
-	defer i.mutex.Unlock()
-	delete(i.sessions, userID)
+	unlock := i.locks.lock(userID)
+	defer unlock()
+	i.sessions.DeleteSession(userID)
 }
@@ -0,0 +1,309 @@
+package vibot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"viget-mvp/internal/extractor"
+	"viget-mvp/internal/models"
+	"viget-mvp/internal/semantic"
+	"viget-mvp/pkg/gpt"
+)
+
+// testBank — короткие интервью из одного вопроса с вариантами: ответ
+// не уходит в модель на анализ, и интервью сразу завершается
+const testBank = `
+version: 1
+interviews:
+  profile:
+    questions:
+      - {id: level, text: "Уровень?", type: choice, required: true, options: [{label: "1"}, {label: "2"}]}
+      - {id: min_budget, text: "С какого бюджета?", type: currency}
+  task:
+    questions:
+      - {id: kind, text: "Что сделать?", type: choice, required: true, options: [{label: "Сайт"}, {label: "Бот"}]}
+`
+
+const testTaskJSON = `{"title": "Сайт", "description": "Лендинг", "required_skills": {"HTML": 3}, "budget": 30000, "deadline_days": 14}`
+
+func newTestInterviewer(t *testing.T, response string) *Interviewer {
+	t.Helper()
+	bank, err := parseQuestionBank([]byte(testBank), "yaml")
+	if err != nil {
+		t.Fatalf("parse bank: %v", err)
+	}
+	i := NewInterviewer(extractor.NewExtractor(gpt.NewFakeClient(response)), nil)
+	i.SetQuestionBank(bank)
+	return i
+}
+
+func TestExtractProfileSignals(t *testing.T) {
+	i := newTestInterviewer(t, `{"name": "Анна", "skills": {"Go": {"level": 4}}, "hours_per_week": 20, "min_budget": 10000}`)
+	const userID = 7
+
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	for _, answer := range []string{"2", "50к"} {
+		if _, _, err := i.ProcessAnswer(userID, answer, nil); err != nil {
+			t.Fatalf("ProcessAnswer(%q): %v", answer, err)
+		}
+	}
+
+	profile, err := i.ExtractProfile(userID, nil, func(*models.UserProfile) error { return nil })
+	if err != nil {
+		t.Fatalf("ExtractProfile: %v", err)
+	}
+	if profile.Availability != 0.5 {
+		t.Errorf("Availability = %v, want 0.5 for 20 hours a week", profile.Availability)
+	}
+	if profile.MinBudget != 50000 {
+		t.Errorf("MinBudget = %d, want the parsed answer 50000", profile.MinBudget)
+	}
+}
+
+func TestExtractTaskKeepsSessionWhenSaveFails(t *testing.T) {
+	i := newTestInterviewer(t, testTaskJSON)
+	const userID = 7
+
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	if _, finished, err := i.ProcessAnswer(userID, "1", nil); err != nil || !finished {
+		t.Fatalf("ProcessAnswer = finished %v, err %v", finished, err)
+	}
+
+	saveErr := errors.New("disk full")
+	_, err := i.ExtractTask(userID, nil, func(*models.TaskProfile) error { return saveErr })
+	if !errors.Is(err, ErrNotSaved) || !errors.Is(err, saveErr) {
+		t.Fatalf("ExtractTask err = %v, want ErrNotSaved wrapping %v", err, saveErr)
+	}
+	if i.GetInterviewType(userID) != "task" {
+		t.Fatal("session deleted although the task was not saved")
+	}
+
+	var saved *models.TaskProfile
+	task, err := i.ExtractTask(userID, nil, func(task *models.TaskProfile) error {
+		saved = task
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExtractTask retry: %v", err)
+	}
+	if saved != task || task.Title != "Сайт" {
+		t.Errorf("saved %+v, returned %+v", saved, task)
+	}
+	if i.GetInterviewType(userID) != "" {
+		t.Error("session kept after the task was saved")
+	}
+}
+
+// blockingLLM отвечает, только когда тест закроет release; started
+// сообщает, что запрос пришел
+type blockingLLM struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (l *blockingLLM) SendRequestContext(ctx context.Context, prompt string) (string, error) {
+	l.started <- struct{}{}
+	select {
+	case <-l.release:
+		return `{}`, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// startTextInterview начинает интервью задачи, первый вопрос которого —
+// текстовый: ответ на него уходит в модель на анализ
+func startTextInterview(t *testing.T, llm gpt.LLM, userID int64) *Interviewer {
+	t.Helper()
+	i := NewInterviewer(extractor.NewExtractor(llm), nil)
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	return i
+}
+
+func TestProcessAnswerAfterCancel(t *testing.T) {
+	llm := &blockingLLM{started: make(chan struct{}), release: make(chan struct{})}
+	const userID = 7
+	i := startTextInterview(t, llm, userID)
+
+	done := make(chan error)
+	go func() {
+		_, _, err := i.ProcessAnswer(userID, "Сделать лендинг для кофейни", nil)
+		done <- err
+	}()
+
+	<-llm.started
+	i.CancelInterview(userID)
+	close(llm.release)
+
+	if err := <-done; err == nil {
+		t.Error("ProcessAnswer succeeded for a cancelled interview")
+	}
+	if i.GetInterviewType(userID) != "" {
+		t.Error("answer recreated the cancelled session")
+	}
+}
+
+func TestProcessAnswerAfterRestart(t *testing.T) {
+	llm := &blockingLLM{started: make(chan struct{}), release: make(chan struct{})}
+	const userID = 7
+	i := startTextInterview(t, llm, userID)
+
+	type result struct {
+		reply string
+		err   error
+	}
+	done := make(chan result)
+	go func() {
+		reply, _, err := i.ProcessAnswer(userID, "Сделать лендинг для кофейни", nil)
+		done <- result{reply, err}
+	}()
+
+	<-llm.started
+	if err := i.StartInterview(userID, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	close(llm.release)
+
+	r := <-done
+	if r.err != nil || !strings.Contains(r.reply, "интервью изменилось") {
+		t.Errorf("ProcessAnswer = %q, %v; want a notice that the interview changed", r.reply, r.err)
+	}
+	if session := i.sessions.GetSession(userID); session == nil || len(session.Answers) != 0 {
+		t.Errorf("stale answer applied to the new interview: %+v", session)
+	}
+}
+
+func TestConcurrentAnswersAndCancels(t *testing.T) {
+	i := NewInterviewer(extractor.NewExtractor(gpt.NewFakeClient(`{}`)), nil)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			userID := int64(g % 2)
+			for n := 0; n < 50; n++ {
+				switch n % 4 {
+				case 0:
+					i.StartInterview(userID, "task")
+				case 3:
+					i.CancelInterview(userID)
+				default:
+					i.ProcessAnswer(userID, "Лендинг для кофейни", nil)
+				}
+				i.GetCurrentQuestion(userID)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestParsedAnswersSkipAnalysis(t *testing.T) {
+	llm := gpt.NewFakeClient("{}")
+	bank, err := parseQuestionBank([]byte(testBank), "yaml")
+	if err != nil {
+		t.Fatalf("parse bank: %v", err)
+	}
+	i := NewInterviewer(extractor.NewExtractor(llm), nil)
+	i.SetQuestionBank(bank)
+	const userID = 7
+
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	for _, answer := range []string{"2", "от 30 до 50к"} {
+		if _, _, err := i.ProcessAnswer(userID, answer, nil); err != nil {
+			t.Fatalf("ProcessAnswer(%q): %v", answer, err)
+		}
+	}
+	if prompts := llm.Prompts(); len(prompts) != 0 {
+		t.Errorf("parsed answers were sent to the model: %d prompts", len(prompts))
+	}
+}
+
+func TestSettersRaceWithInterviews(t *testing.T) {
+	i := newTestInterviewer(t, testTaskJSON)
+	bank, err := parseQuestionBank([]byte(testBank), "yaml")
+	if err != nil {
+		t.Fatalf("parse bank: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for user := int64(1); user <= 4; user++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			for n := 0; n < 20; n++ {
+				if err := i.StartInterview(userID, "task"); err != nil {
+					t.Errorf("StartInterview: %v", err)
+					return
+				}
+				if _, _, err := i.ProcessAnswer(userID, "1", nil); err != nil {
+					t.Errorf("ProcessAnswer: %v", err)
+					return
+				}
+				i.Sweep(time.Now(), time.Hour)
+			}
+		}(user)
+	}
+	for n := 0; n < 20; n++ {
+		i.SetQuestionBank(bank)
+		i.SetSessionTTL(time.Duration(n) * time.Hour)
+	}
+	wg.Wait()
+}
+
+func TestSetIndexWhileExtracting(t *testing.T) {
+	i := newTestInterviewer(t, testTaskJSON)
+	index := semantic.NewIndex(semantic.NewLocalEmbedder(nil), semantic.LocalModel)
+
+	var wg sync.WaitGroup
+	for user := int64(1); user <= 4; user++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			for n := 0; n < 10; n++ {
+				if err := i.StartInterview(userID, "task"); err != nil {
+					t.Errorf("StartInterview: %v", err)
+					return
+				}
+				if _, _, err := i.ProcessAnswer(userID, "1", nil); err != nil {
+					t.Errorf("ProcessAnswer: %v", err)
+					return
+				}
+				if _, err := i.ExtractTask(userID, nil, func(*models.TaskProfile) error { return nil }); err != nil {
+					t.Errorf("ExtractTask: %v", err)
+					return
+				}
+			}
+		}(user)
+	}
+	for n := 0; n < 10; n++ {
+		i.SetIndex(index)
+	}
+	wg.Wait()
+
+	if err := i.StartInterview(9, "task"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	if _, _, err := i.ProcessAnswer(9, "1", nil); err != nil {
+		t.Fatalf("ProcessAnswer: %v", err)
+	}
+	task, err := i.ExtractTask(9, nil, func(*models.TaskProfile) error { return nil })
+	if err != nil {
+		t.Fatalf("ExtractTask: %v", err)
+	}
+	if !index.Fresh(task.Embedding, semantic.TaskTexts(task)) {
+		t.Error("task extracted after SetIndex has no embedding")
+	}
+}
@@ -0,0 +1,44 @@
+package vibot
+
+import "sync"
+
+// userLocks — отдельная блокировка на каждого пользователя: сессии разных
+// пользователей обрабатываются параллельно, одного — строго по очереди.
+// Блокировки создаются по требованию и удаляются, когда их никто не ждет.
+type userLocks struct {
+	mutex sync.Mutex
+	locks map[int64]*userLock
+}
+
+type userLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newUserLocks() *userLocks {
+	return &userLocks{locks: make(map[int64]*userLock)}
+}
+
+// lock захватывает блокировку пользователя и возвращает функцию освобождения
+func (l *userLocks) lock(userID int64) (unlock func()) {
+	l.mutex.Lock()
+	ul, ok := l.locks[userID]
+	if !ok {
+		ul = &userLock{}
+		l.locks[userID] = ul
+	}
+	ul.refs++
+	l.mutex.Unlock()
+
+	ul.Lock()
+	return func() {
+		ul.Unlock()
+
+		l.mutex.Lock()
+		ul.refs--
+		if ul.refs == 0 {
+			delete(l.locks, userID)
+		}
+		l.mutex.Unlock()
+	}
+}
@@ -0,0 +1,41 @@
+package vibot
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUserLocksSerializeOneUser(t *testing.T) {
+	locks := newUserLocks()
+
+	const goroutines, rounds = 8, 200
+	counters := make([]int, 3)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < rounds; n++ {
+				userID := int64(n % len(counters))
+				unlock := locks.lock(userID)
+				counters[userID]++ // без блокировки -race сообщит о гонке
+				unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, c := range counters {
+		total += c
+	}
+	if total != goroutines*rounds {
+		t.Errorf("counted %d increments, want %d", total, goroutines*rounds)
+	}
+
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("%d locks left after all were released", len(locks.locks))
+	}
+}
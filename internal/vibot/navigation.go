@@ -0,0 +1,106 @@
+package vibot
+
+import (
+	"fmt"
+	"time"
+
+	"viget-mvp/internal/models"
+)
+
+type AnsweredQuestion struct {
+	Step     int
+	Question string
+	Answer   string
+}
+
+// GoBack возвращает пользователя к предыдущему вопросу
+func (i *Interviewer) GoBack(userID int64) (string, error) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return "", fmt.Errorf("session not found")
+	}
+	if session.Mode == ModeChat {
+		return "⚠️ В беседе вернуться нельзя — просто уточните ответ следующим сообщением.\n\n" + i.formatQuestion(session), nil
+	}
+	if session.CurrentStep == 0 {
+		return "⚠️ Это первый вопрос, возвращаться некуда.", nil
+	}
+
+	return i.rewind(session, session.CurrentStep-1)
+}
+
+// EditAnswer переводит интервью на вопрос step, чтобы пользователь мог изменить ответ.
+// Остальные ответы сохраняются.
+func (i *Interviewer) EditAnswer(userID int64, step int) (string, error) {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return "", fmt.Errorf("session not found")
+	}
+	i.ensureHistory(session)
+	if _, ok := session.Answers[answerKey(step)]; !ok || step >= len(session.History) {
+		return "", fmt.Errorf("no answer for step %d", step)
+	}
+
+	return i.rewind(session, step)
+}
+
+// AnsweredQuestions возвращает вопросы, на которые уже есть ответ, по порядку
+func (i *Interviewer) AnsweredQuestions(userID int64) []AnsweredQuestion {
+	unlock := i.locks.lock(userID)
+	defer unlock()
+
+	session := i.sessions.GetSession(userID)
+	if session == nil {
+		return nil
+	}
+
+	i.ensureHistory(session)
+	var answered []AnsweredQuestion
+	for step, node := range session.History {
+		answer, ok := session.Answers[answerKey(step)]
+		if !ok {
+			continue
+		}
+		answered = append(answered, AnsweredQuestion{
+			Step:     step,
+			Question: i.bank().GetQuestion(session.Type, node, session.Context),
+			Answer:   fmt.Sprint(answer),
+		})
+	}
+	return answered
+}
+
+func (i *Interviewer) rewind(session *models.InterviewSession, step int) (string, error) {
+	session.CurrentStep = step
+	session.Selected = nil
+	session.UpdatedAt = time.Now()
+	if err := i.sessions.SaveSession(session); err != nil {
+		return "", err
+	}
+
+	question := i.formatQuestion(session)
+	if previous, ok := session.Answers[answerKey(step)]; ok {
+		question += fmt.Sprintf("\n\n✏️ Текущий ответ: %v", previous)
+	}
+	return question, nil
+}
+
+// rebuildContext собирает Context из вкладов ответов в порядке прохождения
+func (i *Interviewer) rebuildContext(session *models.InterviewSession) {
+	session.Context = make(map[string]interface{})
+	for step := range session.History {
+		for k, v := range session.StepContext[answerKey(step)] {
+			session.Context[k] = v
+		}
+	}
+}
+
+func answerKey(step int) string {
+	return fmt.Sprintf("q_%d", step)
+}
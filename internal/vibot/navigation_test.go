@@ -0,0 +1,99 @@
+package vibot
+
+import (
+	"strings"
+	"testing"
+
+	"viget-mvp/internal/extractor"
+	"viget-mvp/pkg/gpt"
+)
+
+// newDefaultInterviewer ведет интервью по встроенному банку; анализ ответов
+// моделью отвечает по rules, а без подходящего правила — пустым контекстом
+func newDefaultInterviewer(rules ...gpt.FakeRule) *Interviewer {
+	return NewInterviewer(extractor.NewExtractor(gpt.NewFakeClient("{}", rules...)), nil)
+}
+
+// answerAll отвечает на вопросы по порядку и возвращает, завершено ли интервью
+func answerAll(t *testing.T, i *Interviewer, userID int64, answers ...string) bool {
+	t.Helper()
+	finished := false
+	for _, answer := range answers {
+		reply, done, err := i.ProcessAnswer(userID, answer, nil)
+		if err != nil {
+			t.Fatalf("ProcessAnswer(%q): %v", answer, err)
+		}
+		if strings.HasPrefix(reply, "⚠️") {
+			t.Fatalf("ProcessAnswer(%q) rejected: %s", answer, reply)
+		}
+		finished = done
+	}
+	return finished
+}
+
+// currentID возвращает идентификатор текущего вопроса
+func currentID(t *testing.T, i *Interviewer, userID int64) string {
+	t.Helper()
+	template, _, ok := i.CurrentTemplate(userID)
+	if !ok {
+		return EndNode
+	}
+	return template.ID
+}
+
+func TestGoBackShowsPreviousAnswer(t *testing.T) {
+	i := newDefaultInterviewer()
+	const userID = 7
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+
+	if reply, err := i.GoBack(userID); err != nil || !strings.Contains(reply, "первый вопрос") {
+		t.Errorf("GoBack on the first question = %q, %v", reply, err)
+	}
+
+	answerAll(t, i, userID, "Анна", "Go и Python")
+	reply, err := i.GoBack(userID)
+	if err != nil {
+		t.Fatalf("GoBack: %v", err)
+	}
+	if got := currentID(t, i, userID); got != "experience" {
+		t.Fatalf("after GoBack current question = %q, want experience", got)
+	}
+	if !strings.Contains(reply, "Текущий ответ: Go и Python") {
+		t.Errorf("GoBack reply does not show the previous answer: %q", reply)
+	}
+}
+
+func TestEditAnswerReturnsToWhereUserStopped(t *testing.T) {
+	i := newDefaultInterviewer()
+	const userID = 7
+	if err := i.StartInterview(userID, "profile"); err != nil {
+		t.Fatalf("StartInterview: %v", err)
+	}
+	answerAll(t, i, userID, "Анна", "Go", "3")
+	if got := currentID(t, i, userID); got != "interests" {
+		t.Fatalf("current question = %q, want interests", got)
+	}
+
+	if _, err := i.EditAnswer(userID, 0); err != nil {
+		t.Fatalf("EditAnswer: %v", err)
+	}
+	if got := currentID(t, i, userID); got != "name" {
+		t.Fatalf("editing question = %q, want name", got)
+	}
+	answerAll(t, i, userID, "Мария")
+
+	// Путь не изменился — остальные ответы сохранены, интервью идет дальше
+	if got := currentID(t, i, userID); got != "interests" {
+		t.Errorf("after edit current question = %q, want interests", got)
+	}
+	answered := i.AnsweredQuestions(userID)
+	if len(answered) != 3 || answered[0].Answer != "Мария" || answered[2].Answer != "3" {
+		t.Errorf("answered = %+v, want the edited name and the kept answers", answered)
+	}
+
+	if _, err := i.EditAnswer(userID, 5); err == nil {
+		t.Error("EditAnswer accepted a step without an answer")
+	}
+}
@@ -4,170 +4,276 @@ package vibot
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
+// QuestionBank хранит интервью как граф вопросов. Вопросы идут по порядку
+// описания, пока переход из Next не укажет другой вопрос или EndNode.
 type QuestionBank struct {
-	profileQuestions []QuestionTemplate
-	taskQuestions    []QuestionTemplate
+	mutex      sync.RWMutex
+	interviews map[string][]QuestionTemplate
 }
 
+// EndNode — переход, завершающий интервью досрочно
+const EndNode = "end"
+
 type QuestionTemplate struct {
+	ID       string
 	Text     string
 	Required bool
-	Type     string // "text", "number", "choice"
+	Type     string // "text", "number", "currency", "deadline", "choice"
+	// Min и Max ограничивают "number" и "currency" (nil — без ограничения)
+	Min     *float64
+	Max     *float64
+	Options []Option // варианты для "choice"
+	// Multiple разрешает выбрать несколько вариантов
+	Multiple bool
+	// Variants — формулировки, зависящие от контекста сессии
+	Variants []Variant
+	// Next — переходы после ответа; срабатывает первый подходящий,
+	// иначе интервью идет к следующему по порядку вопросу
+	Next []Transition
+	// Validate — дополнительная проверка сырого ответа после разбора по типу
 	Validate func(string) bool
 }
 
+// Option — вариант ответа на вопрос типа "choice".
+// Context подставляется в контекст сессии вместо анализа ответа через GPT.
+type Option struct {
+	Label   string
+	Value   string
+	Context map[string]interface{}
+}
+
+// OptionValue возвращает значение варианта (по умолчанию — его текст)
+func (o Option) OptionValue() string {
+	if o.Value != "" {
+		return o.Value
+	}
+	return o.Label
+}
+
+// Variant заменяет текст вопроса, если все условия When выполнены.
+// Значение "*" означает любое непустое значение ключа контекста.
+type Variant struct {
+	When map[string]string
+	Text string
+}
+
+// Transition ведет к вопросу Goto, если выполнены все условия When.
+// Ключ "answer" проверяет разобранный ответ на текущий вопрос,
+// остальные ключи — контекст сессии. "*" означает любое непустое значение.
+type Transition struct {
+	When map[string]string
+	Goto string
+}
+
+// NewQuestionBank возвращает встроенный банк вопросов (banks/default.yaml)
 func NewQuestionBank() *QuestionBank {
-	return &QuestionBank{
-		profileQuestions: []QuestionTemplate{
-			{
-				Text:     "👋 Привет! Давайте знакомиться. Как вас зовут?",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "💻 Расскажите о своем опыте в IT. Какими технологиями владеете? (например: Python, JavaScript, React)",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "📊 Оцените свой общий уровень в программировании от 1 до 5, где:\n1 - начинающий\n2 - базовые знания\n3 - уверенный пользователь\n4 - продвинутый\n5 - эксперт",
-				Required: true,
-				Type:     "number",
-				Validate: func(s string) bool {
-					return strings.Contains("12345", s) && len(s) == 1
-				},
-			},
-			{
-				Text:     "🎯 Что вас интересует в работе? Какие проекты хотели бы делать? (веб-разработка, мобильные приложения, данные, дизайн и т.д.)",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "🤝 Расскажите о своих сильных сторонах в работе. Что у вас получается особенно хорошо?",
-				Required: false,
-				Type:     "text",
-			},
-			{
-				Text:     "🚀 Какие профессиональные цели хотите достичь в ближайшее время?",
-				Required: false,
-				Type:     "text",
-			},
-			{
-				Text:     "💼 Есть ли у вас опыт удаленной работы или фриланса? Если да, расскажите кратко.",
-				Required: false,
-				Type:     "text",
-			},
-		},
-		taskQuestions: []QuestionTemplate{
-			{
-				Text:     "📝 Как называется ваша задача? Придумайте краткое и понятное название.",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "📋 Опишите подробно, что нужно сделать. Какой результат вы ожидаете получить?",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "🛠️ Какие технические навыки нужны исполнителю? Укажите технологии и желаемый уровень (например: Python - 3/5, React - 2/5)",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "💰 Какой бюджет вы готовы выделить на эту задачу? Укажите сумму в рублях.",
-				Required: true,
-				Type:     "number",
-			},
-			{
-				Text:     "⏰ В какие сроки нужно выполнить задачу? Укажите количество дней или конкретную дату.",
-				Required: true,
-				Type:     "text",
-			},
-			{
-				Text:     "⭐ Есть ли особые требования к исполнителю? (опыт, портфолио, общение и т.д.)",
-				Required: false,
-				Type:     "text",
-			},
-		},
-	}
-}
-
-func (q *QuestionBank) GetQuestion(interviewType string, step int, context map[string]interface{}) string {
-	var questions []QuestionTemplate
-
-	switch interviewType {
-	case "profile":
-		questions = q.profileQuestions
-	case "task":
-		questions = q.taskQuestions
-	default:
+	bank, err := parseQuestionBank(defaultBank, "yaml")
+	if err != nil {
+		panic(fmt.Sprintf("built-in question bank is invalid: %v", err))
+	}
+	return bank
+}
+
+func (q *QuestionBank) GetQuestion(interviewType string, nodeID string, context map[string]interface{}) string {
+	q.mutex.RLock()
+	_, ok := q.interviews[interviewType]
+	q.mutex.RUnlock()
+
+	if !ok {
 		return "❌ Неизвестный тип интервью"
 	}
 
-	if step >= len(questions) {
+	question, ok := q.GetTemplate(interviewType, nodeID)
+	if !ok {
 		return "✅ Интервью завершено"
 	}
 
-	question := questions[step]
+	text := question.Text
 
 	// Адаптируем вопрос на основе контекста
 	if len(context) > 0 {
-		return q.adaptQuestion(question.Text, step, interviewType, context)
+		text = q.adaptQuestion(question, context)
 	}
 
-	return question.Text
+	if !question.Required {
+		text += "\n\n⏭️ Необязательный вопрос — отправьте «-», чтобы пропустить."
+	}
+
+	return text
 }
 
-func (q *QuestionBank) GetMaxSteps(interviewType string) int {
-	switch interviewType {
-	case "profile":
-		return len(q.profileQuestions)
-	case "task":
-		return len(q.taskQuestions)
-	default:
-		return 0
+// GetTemplate возвращает описание вопроса без адаптации текста
+func (q *QuestionBank) GetTemplate(interviewType string, nodeID string) (QuestionTemplate, bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, question := range q.interviews[interviewType] {
+		if question.ID == nodeID {
+			return question, true
+		}
+	}
+	return QuestionTemplate{}, false
+}
+
+// First возвращает первый вопрос интервью
+func (q *QuestionBank) First(interviewType string) string {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	questions := q.interviews[interviewType]
+	if len(questions) == 0 {
+		return EndNode
 	}
+	return questions[0].ID
 }
 
-func (q *QuestionBank) adaptQuestion(question string, step int, interviewType string, context map[string]interface{}) string {
-	if interviewType == "profile" {
-		// Адаптация для профильных вопросов
-		if step == 1 && context["mentioned_skills"] != nil {
-			if skills, ok := context["mentioned_skills"].([]interface{}); ok && len(skills) > 0 {
-				return fmt.Sprintf("💻 Вы упомянули %v. Расскажите подробнее о вашем опыте с этими технологиями и оцените свой уровень по каждой (1-5).", skills)
+// Next выбирает вопрос после nodeID по ответу и контексту.
+// Возвращает EndNode, если интервью закончено.
+func (q *QuestionBank) Next(interviewType, nodeID string, answer interface{}, context map[string]interface{}) string {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	questions := q.interviews[interviewType]
+	for idx, question := range questions {
+		if question.ID != nodeID {
+			continue
+		}
+
+		for _, transition := range question.Next {
+			if transition.matches(answer, context) {
+				return transition.Goto
 			}
 		}
+		if idx+1 < len(questions) {
+			return questions[idx+1].ID
+		}
+		return EndNode
+	}
+
+	// Вопрос исчез после перечитывания банка — завершаем интервью
+	return EndNode
+}
 
-		if step == 3 && context["experience_level"] != nil {
-			level := context["experience_level"].(string)
-			switch level {
-			case "junior":
-				return "🌱 Как начинающий специалист, какие проекты вас больше всего привлекают для получения опыта?"
-			case "senior":
-				return "🚀 С вашим опытом, какие сложные и интересные задачи вы готовы решать?"
+// Remaining оценивает, сколько вопросов осталось начиная с nodeID включительно,
+// если дальше идти по переходам, не зависящим от будущих ответов
+func (q *QuestionBank) Remaining(interviewType, nodeID string, context map[string]interface{}) int {
+	visited := make(map[string]bool)
+	count := 0
+	for nodeID != EndNode && !visited[nodeID] {
+		if _, ok := q.GetTemplate(interviewType, nodeID); !ok {
+			break
+		}
+		visited[nodeID] = true
+		count++
+		nodeID = q.Next(interviewType, nodeID, nil, context)
+	}
+	return count
+}
+
+func (q *QuestionBank) GetMaxSteps(interviewType string) int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return len(q.interviews[interviewType])
+}
+
+// replace подменяет содержимое банка, например после перечитывания файла
+func (q *QuestionBank) replace(other *QuestionBank) {
+	other.mutex.RLock()
+	interviews := other.interviews
+	other.mutex.RUnlock()
+
+	q.mutex.Lock()
+	q.interviews = interviews
+	q.mutex.Unlock()
+}
+
+func (q *QuestionBank) adaptQuestion(question QuestionTemplate, context map[string]interface{}) string {
+	for _, variant := range question.Variants {
+		if variant.matches(context) {
+			return renderVariant(variant.Text, context)
+		}
+	}
+	return question.Text
+}
+
+func (v Variant) matches(context map[string]interface{}) bool {
+	for key, expected := range v.When {
+		if !valueMatches(context[key], expected) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t Transition) matches(answer interface{}, context map[string]interface{}) bool {
+	for key, expected := range t.When {
+		value := context[key]
+		if key == "answer" {
+			value = answer
+		}
+		if !valueMatches(value, expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// valueMatches сравнивает значение с ожидаемым; для списков достаточно одного совпадения
+func valueMatches(value interface{}, expected string) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if valueMatches(item, expected) {
+				return true
 			}
 		}
-	} else if interviewType == "task" {
-		// Адаптация для вопросов о задачах
-		if step == 2 && context["task_complexity"] != nil {
-			complexity := context["task_complexity"].(string)
-			switch complexity {
-			case "simple":
-				return "🛠️ Для простой задачи укажите базовые навыки, которые нужны исполнителю (например: HTML/CSS - 2/5, базовый JavaScript - 1/5)"
-			case "complex":
-				return "🔧 Для сложной задачи детально опишите требования к навыкам и опыту (например: React - 4/5, Node.js - 3/5, опыт с API)"
+		return false
+	case []string:
+		for _, item := range v {
+			if valueMatches(item, expected) {
+				return true
 			}
 		}
+		return false
+	}
 
-		if step == 3 && context["mentioned_technologies"] != nil {
-			if techs, ok := context["mentioned_technologies"].([]interface{}); ok && len(techs) > 0 {
-				return fmt.Sprintf("💰 Учитывая использование %v, какой бюджет подходит для данной задачи? (укажите сумму в рублях)", techs)
+	s := contextString(value)
+	if s == "" {
+		return false
+	}
+	return expected == "*" || strings.EqualFold(s, expected)
+}
+
+// renderVariant подставляет {{ключ}} значениями из контекста
+func renderVariant(text string, context map[string]interface{}) string {
+	for key, value := range context {
+		text = strings.ReplaceAll(text, "{{"+key+"}}", contextString(value))
+	}
+	return text
+}
+
+// contextString приводит значение контекста к строке: списки — через запятую
+func contextString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := contextString(item); s != "" {
+				parts = append(parts, s)
 			}
 		}
+		return strings.Join(parts, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
 	}
-
-	return question
 }
@@ -5,6 +5,16 @@ import (
 	"viget-mvp/internal/models"
 )
 
+// SessionStore хранит незавершенные интервью. Долговременная реализация —
+// profile.SQLiteStorage, благодаря ей сессия переживает перезапуск бота.
+type SessionStore interface {
+	SaveSession(session *models.InterviewSession) error
+	GetSession(userID int64) *models.InterviewSession
+	DeleteSession(userID int64) error
+	ListSessions() []*models.InterviewSession
+}
+
+// SessionStorage — хранилище сессий в памяти
 type SessionStorage struct {
 	mu       sync.RWMutex
 	sessions map[int64]*models.InterviewSession
@@ -16,28 +26,32 @@ func NewSessionStorage() *SessionStorage {
 	}
 }
 
-func (s *SessionStorage) Create(session *models.InterviewSession) {
+func (s *SessionStorage) SaveSession(session *models.InterviewSession) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.sessions[session.UserID] = session
+	return nil
 }
 
-func (s *SessionStorage) Get(userID int64) (*models.InterviewSession, bool) {
+func (s *SessionStorage) GetSession(userID int64) *models.InterviewSession {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	sess, ok := s.sessions[userID]
-	return sess, ok
+	return s.sessions[userID]
 }
 
-func (s *SessionStorage) Delete(userID int64) {
+func (s *SessionStorage) DeleteSession(userID int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	delete(s.sessions, userID)
+	return nil
 }
 
-func (s *SessionStorage) Exists(userID int64) bool {
+func (s *SessionStorage) ListSessions() []*models.InterviewSession {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	_, ok := s.sessions[userID]
-	return ok
+	sessions := make([]*models.InterviewSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
 }
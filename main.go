@@ -2,10 +2,15 @@ package main
 
 import (
 	"log"
+	"time"
 	"viget-mvp/config"
 	"viget-mvp/internal/bot"
+	"viget-mvp/internal/extractor"
 	"viget-mvp/internal/matcher"
 	"viget-mvp/internal/profile"
+	"viget-mvp/internal/semantic"
+	"viget-mvp/internal/skills"
+	"viget-mvp/internal/usage"
 	"viget-mvp/internal/vibot"
 	"viget-mvp/pkg/gpt"
 
@@ -21,21 +26,156 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// GPT Client
-	gptClient := gpt.NewClient(cfg.GPTToken)
+	// Учет расхода токенов по пользователям и функциям
+	tracker := usage.NewTracker(usage.Price{Input: cfg.GPTPriceInput, Output: cfg.GPTPriceOutput}, cfg.UserDailyTokens)
 
-	// In-memory storage
-	storage := profile.NewInMemoryStorage()
+	// LLM: OpenAI-совместимый API или сценарий без сети
+	var gptClient gpt.LLM
+	switch cfg.LLMProvider {
+	case "fake":
+		fakeClient := gpt.NewFakeClient("{}")
+		if cfg.LLMFakeScript != "" {
+			if fakeClient, err = gpt.LoadFakeClient(cfg.LLMFakeScript); err != nil {
+				log.Fatal(err)
+			}
+		}
+		fakeClient.SetUsageHook(tracker.Record)
+		gptClient = fakeClient
+		log.Println("Using fake LLM provider.")
+	default:
+		openAIClient := gpt.NewOpenAIClient(cfg.GPTToken, cfg.GPTBaseURL, cfg.GPTModel)
+		openAIClient.SetTimeout(cfg.GPTTimeout)
+		openAIClient.SetRetries(cfg.GPTMaxRetries, 500*time.Millisecond, 30*time.Second)
+		openAIClient.SetUsageHook(tracker.Record)
+		gptClient = openAIClient
+	}
+	// Запросы пользователя, исчерпавшего дневной лимит, до модели не доходят
+	gptClient = tracker.Wrap(gptClient)
+
+	// Повторные детерминированные запросы отвечаются из кэша и не расходуют лимит
+	var llmCache *gpt.Cache
+	if cfg.LLMCacheTTL > 0 {
+		model := cfg.GPTModel
+		if cfg.LLMProvider == "fake" {
+			model = "fake"
+		}
+		llmCache, err = gpt.NewCache(gptClient, model, gpt.CacheOptions{
+			TTL:        cfg.LLMCacheTTL,
+			MaxEntries: cfg.LLMCacheMaxEntries,
+			MaxBytes:   cfg.LLMCacheMaxBytes,
+			Dir:        cfg.LLMCacheDir,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		gptClient = llmCache
+	}
+
+	// Storage
+	var storage profile.Store
+	switch cfg.StorageDriver {
+	case "sqlite":
+		sqliteStorage, err := profile.NewSQLiteStorage(cfg.SQLitePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer sqliteStorage.Close()
+		storage = sqliteStorage
+	default:
+		storage = profile.NewInMemoryStorage()
+	}
+	if cfg.SeedDemoTasks {
+		if err := profile.SeedDemoTasks(storage); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Interviewer (сессии хранятся там же, где профили)
+	// Промпты: встроенные версии, дополнительные из каталога и A/B эксперименты
+	ext := extractor.NewExtractor(gptClient)
+	if cfg.PromptsDir != "" {
+		if err := ext.Prompts().LoadDir(cfg.PromptsDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+	experiments, err := extractor.ParseExperiments(cfg.PromptExperiments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, experiment := range experiments {
+		if err := ext.Prompts().SetExperiment(experiment); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Prompt experiment: %s/%s on %d%% of sessions", experiment.Name, experiment.Candidate, experiment.Percent)
+	}
+
+	// Таксономия навыков: встроенные синонимы и правки администратора из хранилища
+	taxonomy := skills.NewTaxonomy(skills.Builtin)
+	taxonomy.SetStore(storage)
 
-	// Interviewer
-	interviewer := vibot.NewInterviewer(gptClient)
+	// Векторы интересов и задач: локально или через API векторных представлений
+	var index *semantic.Index
+	switch cfg.EmbeddingProvider {
+	case "openai":
+		embedder := gpt.NewOpenAIClient(cfg.GPTToken, cfg.GPTBaseURL, cfg.GPTModel)
+		embedder.SetEmbeddingModel(cfg.EmbeddingModel)
+		embedder.SetTimeout(cfg.GPTTimeout)
+		embedder.SetRetries(cfg.GPTMaxRetries, 500*time.Millisecond, 30*time.Second)
+		embedder.SetUsageHook(tracker.Record)
+		tracker.SetPrice(embedder.EmbeddingModel(), usage.Price{Input: cfg.EmbeddingPrice})
+		index = semantic.NewIndex(embedder, embedder.EmbeddingModel())
+	default:
+		index = semantic.NewIndex(semantic.NewLocalEmbedder(taxonomy), semantic.LocalModel)
+	}
+
+	interviewer := vibot.NewInterviewer(ext, storage)
+	interviewer.SetTaxonomy(taxonomy)
+	interviewer.SetIndex(index)
+	interviewer.SetSessionTTL(cfg.SessionTTL)
+	if cfg.InterviewMode == "chat" {
+		interviewer.SetChatMode(vibot.ChatLimits{MaxTurns: cfg.ChatMaxTurns, MaxTokens: cfg.ChatMaxTokens})
+		log.Printf("Profile interviews are conducted as a chat (up to %d turns)", cfg.ChatMaxTurns)
+	}
 
-	// Matcher
-	matcherService := matcher.NewMatcher()
+	// Банк вопросов из файла проверяется при старте и перечитывается при изменении
+	if cfg.QuestionBankPath != "" {
+		bank, err := vibot.LoadQuestionBank(cfg.QuestionBankPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		interviewer.SetQuestionBank(bank)
+		go bank.Watch(cfg.QuestionBankPath, cfg.QuestionBankReload, nil)
+	}
+
+	// Matcher: веса факторов и порог из конфигурации
+	weights, err := matcher.ParseWeights(cfg.MatchWeights)
+	if err != nil {
+		log.Fatalf("Invalid MATCH_WEIGHTS: %v", err)
+	}
+	matchConfig := matcher.DefaultConfig().WithWeights(weights)
+	matchConfig.Threshold = cfg.MatchThreshold
+	matchConfig.DeadlineDays = cfg.MatchDeadlineDays
+	matchConfig.InterestSimilarity = cfg.MatchInterestSimilarity
+	matcherService, err := matcher.NewMatcher(matchConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	matcherService.SetTaxonomy(taxonomy)
+
+	// Подбор читает сохраненные векторы; устаревшие пересчитываются в фоне
+	index.StartRefresher(storage, cfg.EmbeddingRefreshInterval)
 
 	// Handler (Telegram bot logic)
 	handler := bot.NewHandler(botAPI, storage, interviewer, matcherService)
+	handler.SetAdmins(cfg.AdminIDs)
+	handler.SetUsageTracker(tracker)
+	handler.SetTaxonomy(taxonomy)
+	if llmCache != nil {
+		handler.SetLLMCache(llmCache)
+	}
+
+	handler.StartSessionSweeper(cfg.SessionSweepInterval, cfg.SessionRemindAfter)
 
 	log.Println("Bot started.")
-	handler.Start()
+	handler.Start(cfg.BotWorkers)
 }
@@ -0,0 +1,321 @@
+package gpt
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheOptions — ограничения кэша ответов
+type CacheOptions struct {
+	// TTL — сколько ответ остается годным (0 — бессрочно)
+	TTL time.Duration
+	// MaxEntries и MaxBytes ограничивают число и суммарный размер ответов (0 — без ограничения)
+	MaxEntries int
+	MaxBytes   int
+	// Dir — каталог для хранения ответов на диске (пусто — только в памяти)
+	Dir string
+}
+
+// CacheStats — метрики кэша
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	Bytes     int
+}
+
+// Invalidator — кэш, из которого можно убрать негодный ответ
+type Invalidator interface {
+	Invalidate(ctx context.Context, prompt string)
+}
+
+// Cache отвечает на повторные детерминированные запросы без обращения к модели.
+// Кэшируются только запросы с версией промпта в контексте (WithPromptVersion);
+// ключ — модель, версия промпта, режим JSON и промпт без лишних пробелов.
+// Вытесняются давно не использованные ответы.
+type Cache struct {
+	llm   LLM
+	model string
+	opts  CacheOptions
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // от недавно использованных к давним
+	bytes   int
+
+	hits, misses, evictions int64
+	now                     func() time.Time
+}
+
+// cacheEntry — ответ в памяти и файл на диске
+type cacheEntry struct {
+	Key       string    `json:"key"`
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	_ LLM           = (*Cache)(nil)
+	_ JSONRequester = (*Cache)(nil)
+	_ Invalidator   = (*Cache)(nil)
+)
+
+// NewCache создает кэш перед llm. model входит в ключ, чтобы смена модели
+// не отдавала ответы прежней. Если задан opts.Dir, сохраненные ответы
+// загружаются с диска.
+func NewCache(llm LLM, model string, opts CacheOptions) (*Cache, error) {
+	c := &Cache{
+		llm:     llm,
+		model:   model,
+		opts:    opts,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return nil, err
+		}
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Cache) SendRequestContext(ctx context.Context, prompt string) (string, error) {
+	return c.cached(ctx, prompt, false, func() (string, error) {
+		return c.llm.SendRequestContext(ctx, prompt)
+	})
+}
+
+func (c *Cache) SendJSONRequestContext(ctx context.Context, prompt string) (string, error) {
+	return c.cached(ctx, prompt, true, func() (string, error) {
+		if requester, ok := c.llm.(JSONRequester); ok {
+			return requester.SendJSONRequestContext(ctx, prompt)
+		}
+		return c.llm.SendRequestContext(ctx, prompt)
+	})
+}
+
+// Invalidate убирает ответ на prompt, например не прошедший проверку схемы,
+// чтобы повторный запрос снова ушел к модели
+func (c *Cache) Invalidate(ctx context.Context, prompt string) {
+	version := PromptVersionFrom(ctx)
+	if version == "" {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, jsonMode := range []bool{false, true} {
+		if elem, ok := c.entries[c.key(version, jsonMode, prompt)]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+// Stats возвращает метрики кэша
+func (c *Cache) Stats() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+	}
+}
+
+func (c *Cache) cached(ctx context.Context, prompt string, jsonMode bool, send func() (string, error)) (string, error) {
+	version := PromptVersionFrom(ctx)
+	if version == "" {
+		return send()
+	}
+	key := c.key(version, jsonMode, prompt)
+
+	if response, ok := c.get(key); ok {
+		// Ожидающий ответа видит его так же, как пришедший от модели
+		if progress := ProgressFrom(ctx); progress != nil && response != "" {
+			progress(response)
+		}
+		return response, nil
+	}
+
+	response, err := send()
+	if err != nil {
+		return "", err
+	}
+	c.put(key, response)
+	return response, nil
+}
+
+// key — хеш модели, версии промпта, режима и нормализованного промпта
+func (c *Cache) key(version string, jsonMode bool, prompt string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%t\x00%s", c.model, version, jsonMode, normalizePrompt(prompt))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizePrompt схлопывает пробелы и переводы строк: ответы, отличающиеся
+// только ими, дают тот же ключ
+func normalizePrompt(prompt string) string {
+	return strings.Join(strings.Fields(prompt), " ")
+}
+
+func (c *Cache) get(key string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[key]
+	if ok && c.expired(elem.Value.(*cacheEntry)) {
+		c.remove(elem)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return "", false
+	}
+
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).Response, true
+}
+
+func (c *Cache) put(key, response string) {
+	if c.opts.MaxBytes > 0 && len(response) > c.opts.MaxBytes {
+		return
+	}
+
+	entry := &cacheEntry{Key: key, Response: response}
+	c.mutex.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	entry.CreatedAt = c.now()
+	c.add(entry)
+	c.evict()
+	c.mutex.Unlock()
+
+	c.persist(entry)
+}
+
+func (c *Cache) add(entry *cacheEntry) {
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	c.bytes += len(entry.Response)
+}
+
+// remove удаляет ответ из памяти и с диска
+func (c *Cache) remove(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.Key)
+	c.bytes -= len(entry.Response)
+	if c.opts.Dir != "" {
+		if err := os.Remove(c.path(entry.Key)); err != nil && !os.IsNotExist(err) {
+			log.Printf("llm cache: %v", err)
+		}
+	}
+}
+
+// evict вытесняет давно не использованные ответы сверх ограничений
+func (c *Cache) evict() {
+	for c.order.Len() > 0 &&
+		((c.opts.MaxEntries > 0 && c.order.Len() > c.opts.MaxEntries) ||
+			(c.opts.MaxBytes > 0 && c.bytes > c.opts.MaxBytes)) {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache) expired(entry *cacheEntry) bool {
+	return c.opts.TTL > 0 && c.now().Sub(entry.CreatedAt) > c.opts.TTL
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.opts.Dir, key+".json")
+}
+
+// persist записывает ответ на диск вне блокировки, чтобы медленный диск не
+// задерживал другие запросы. Файл появляется, только если ответ к этому
+// моменту не вытеснен и не заменен. Кэш остается рабочим и при ошибке записи.
+func (c *Cache) persist(entry *cacheEntry) {
+	if c.opts.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("llm cache: %v", err)
+		return
+	}
+	tmp, err := os.CreateTemp(c.opts.Dir, entry.Key+"-*.tmp")
+	if err != nil {
+		log.Printf("llm cache: %v", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("llm cache: %v", err)
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.entries[entry.Key]; !ok || elem.Value.(*cacheEntry) != entry {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(entry.Key)); err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("llm cache: %v", err)
+	}
+}
+
+// load читает ответы с диска, пропуская устаревшие и поврежденные файлы
+func (c *Cache) load() error {
+	files, err := filepath.Glob(filepath.Join(c.opts.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	var loaded []*cacheEntry
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var entry cacheEntry
+		if json.Unmarshal(data, &entry) != nil || entry.Key+".json" != filepath.Base(file) || c.expired(&entry) {
+			os.Remove(file)
+			continue
+		}
+		loaded = append(loaded, &entry)
+	}
+
+	// Давние ответы в конце очереди — их вытесняем первыми
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].CreatedAt.Before(loaded[j].CreatedAt)
+	})
+	for _, entry := range loaded {
+		c.add(entry)
+	}
+	c.evict()
+	return nil
+}
@@ -0,0 +1,70 @@
+package gpt
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCacheHitReportsProgress(t *testing.T) {
+	cache, err := NewCache(NewFakeClient(`{"ok":true}`), "fake", CacheOptions{})
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	ctx := WithPromptVersion(context.Background(), "profile/v1")
+	if _, err := cache.SendRequestContext(ctx, "prompt"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	var shown string
+	ctx = WithProgress(ctx, func(text string) { shown = text })
+	response, err := cache.SendRequestContext(ctx, "prompt")
+	if err != nil {
+		t.Fatalf("cached request: %v", err)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 {
+		t.Fatalf("Hits = %d, want 1", stats.Hits)
+	}
+	if shown != response {
+		t.Errorf("progress got %q, want the cached response %q", shown, response)
+	}
+}
+
+func TestCachePersistsOnlyLiveEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(NewFakeClient("{}"), "fake", CacheOptions{Dir: dir, MaxEntries: 5})
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+
+	ctx := WithPromptVersion(context.Background(), "profile/v1")
+	var wg sync.WaitGroup
+	for n := 0; n < 50; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			if _, err := cache.SendRequestContext(ctx, fmt.Sprintf("prompt %d", n)); err != nil {
+				t.Errorf("request %d: %v", n, err)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := cache.Stats().Entries; len(files) != entries {
+		t.Errorf("%d files on disk for %d cached entries: %v", len(files), entries, files)
+	}
+
+	reloaded, err := NewCache(NewFakeClient("{}"), "fake", CacheOptions{Dir: dir, MaxEntries: 5})
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got, want := reloaded.Stats().Entries, cache.Stats().Entries; got != want {
+		t.Errorf("reloaded %d entries, want %d", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package gpt
+
+import (
+	"context"
+	"strings"
+)
+
+// Роли сообщений диалога
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Chatter — модель, принимающая диалог целиком: системный промпт и историю сообщений
+type Chatter interface {
+	ChatContext(ctx context.Context, messages []Message) (string, error)
+}
+
+var (
+	_ Chatter = (*Client)(nil)
+	_ Chatter = (*FakeClient)(nil)
+	_ Chatter = (*Cache)(nil)
+)
+
+// Chat отправляет диалог модели. Если модель не умеет принимать историю,
+// диалог склеивается в один промпт.
+func Chat(ctx context.Context, llm LLM, messages []Message) (string, error) {
+	if chatter, ok := llm.(Chatter); ok {
+		return chatter.ChatContext(ctx, messages)
+	}
+	return llm.SendRequestContext(ctx, flatten(messages))
+}
+
+// ChatContext отправляет диалог messages с теми же повторами, что SendRequestContext
+func (c *Client) ChatContext(ctx context.Context, messages []Message) (string, error) {
+	return c.complete(ctx, ChatRequest{Model: c.model, Messages: messages})
+}
+
+// ChatContext отвечает по сценарию; правила проверяются на всем диалоге
+func (c *FakeClient) ChatContext(ctx context.Context, messages []Message) (string, error) {
+	return c.SendRequestContext(ctx, flatten(messages))
+}
+
+// ChatContext не кэширует диалоги — ответ зависит от всей истории
+func (c *Cache) ChatContext(ctx context.Context, messages []Message) (string, error) {
+	return Chat(ctx, c.llm, messages)
+}
+
+func flatten(messages []Message) string {
+	parts := make([]string, len(messages))
+	for n, m := range messages {
+		parts[n] = m.Role + ": " + m.Content
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -2,22 +2,49 @@ package gpt
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 type Client struct {
-	apiKey  string
-	baseURL string
-	model   string
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+
+	// timeout ограничивает одну попытку запроса (0 — без ограничения)
+	timeout time.Duration
+	// maxRetries — сколько раз повторить запрос после временной ошибки
+	maxRetries int
+	// baseDelay и maxDelay задают экспоненциальную задержку между попытками
+	baseDelay time.Duration
+	maxDelay  time.Duration
+
+	// usageHook получает расход токенов каждого успешного запроса
+	usageHook UsageHook
+	// embeddingModel — модель для Embed
+	embeddingModel string
 }
 
 type ChatRequest struct {
-	Model    string    `json:"model"`
-	Messages []Message `json:"messages"`
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	Stream         bool            `json:"stream,omitempty"`
+	StreamOptions  *StreamOptions  `json:"stream_options,omitempty"`
+}
+
+// ResponseFormat{Type: "json_object"} включает JSON mode
+type ResponseFormat struct {
+	Type string `json:"type"`
 }
 
 type Message struct {
@@ -27,26 +54,98 @@ type Message struct {
 
 type ChatResponse struct {
 	Choices []Choice `json:"choices"`
+	// Usage — расход токенов; некоторые совместимые API его не возвращают
+	Usage *Usage `json:"usage,omitempty"`
 }
 
 type Choice struct {
 	Message Message `json:"message"`
 }
 
+// errorResponse — тело ответа об ошибке в формате OpenAI
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// DefaultBaseURL — адрес API OpenAI; для своей модели подойдет любой
+// OpenAI-совместимый endpoint (vLLM, Ollama, LM Studio и т.п.)
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+const DefaultModel = "gpt-4-1106-preview" // GPT-4.1-mini
+
+const (
+	DefaultTimeout    = 60 * time.Second
+	DefaultMaxRetries = 3
+)
+
 func NewClient(apiKey string) *Client {
-	model := os.Getenv("GPT_MODEL")
+	return NewOpenAIClient(apiKey, DefaultBaseURL, os.Getenv("GPT_MODEL"))
+}
+
+// NewOpenAIClient создает клиент OpenAI-совместимого API по адресу baseURL.
+// Пустые baseURL и model заменяются значениями по умолчанию.
+func NewOpenAIClient(apiKey, baseURL, model string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 	if model == "" {
-		model = "gpt-4-1106-preview" // GPT-4.1-mini
+		model = DefaultModel
 	}
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: "https://api.openai.com/v1",
-		model:   model,
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      model,
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  500 * time.Millisecond,
+		maxDelay:   30 * time.Second,
+
+		embeddingModel: DefaultEmbeddingModel,
 	}
 }
 
+// SetTimeout задает предельное время одной попытки запроса
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetRetries задает число повторов и границы задержки между ними
+func (c *Client) SetRetries(maxRetries int, baseDelay, maxDelay time.Duration) {
+	c.maxRetries = maxRetries
+	c.baseDelay = baseDelay
+	c.maxDelay = maxDelay
+}
+
+// SetUsageHook задает получателя расхода токенов (nil — не учитывать)
+func (c *Client) SetUsageHook(hook UsageHook) {
+	c.usageHook = hook
+}
+
 func (c *Client) SendRequest(prompt string) (string, error) {
-	request := ChatRequest{
+	return c.SendRequestContext(context.Background(), prompt)
+}
+
+// SendRequestContext отправляет промпт, повторяя запрос при 429, 5xx и сетевых
+// ошибках с экспоненциальной задержкой и Retry-After. Ошибки API оборачивают
+// ErrRateLimited, ErrAuth, ErrQuota, ErrBadRequest или ErrUnavailable.
+func (c *Client) SendRequestContext(ctx context.Context, prompt string) (string, error) {
+	return c.complete(ctx, c.chatRequest(prompt))
+}
+
+// SendJSONRequestContext — как SendRequestContext, но в JSON mode:
+// модель обязана вернуть один JSON объект
+func (c *Client) SendJSONRequestContext(ctx context.Context, prompt string) (string, error) {
+	request := c.chatRequest(prompt)
+	request.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	return c.complete(ctx, request)
+}
+
+func (c *Client) chatRequest(prompt string) ChatRequest {
+	return ChatRequest{
 		Model: c.model,
 		Messages: []Message{
 			{
@@ -55,45 +154,157 @@ func (c *Client) SendRequest(prompt string) (string, error) {
 			},
 		},
 	}
+}
+
+func (c *Client) complete(ctx context.Context, request ChatRequest) (string, error) {
+	progress := ProgressFrom(ctx)
+	if progress != nil {
+		request.Stream = true
+		request.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	var response *ChatResponse
+	err = c.retry(ctx, func() (err error) {
+		if progress != nil {
+			response, err = c.sendStream(ctx, jsonData, progress)
+		} else {
+			response, err = c.send(ctx, jsonData)
+		}
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
 
+	content := response.Choices[0].Message.Content
+	usage := EstimateUsage(flatten(request.Messages), content)
+	if response.Usage != nil {
+		usage = *response.Usage
+	}
+	recordUsage(ctx, usage)
+	if c.usageHook != nil {
+		c.usageHook(ctx, c.model, usage)
+	}
+	return content, nil
+}
+
+// retry вызывает try, повторяя временные ошибки с экспоненциальной задержкой
+func (c *Client) retry(ctx context.Context, try func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := try()
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= c.maxRetries || !retryable(ctx, err) {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// send выполняет одну попытку запроса
+func (c *Client) send(ctx context.Context, jsonData []byte) (*ChatResponse, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("API error: %s", string(body))
+		return nil, newAPIError(resp, body)
 	}
 
 	var chatResponse ChatResponse
 	err = json.Unmarshal(body, &chatResponse)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(chatResponse.Choices) == 0 {
-		return "", fmt.Errorf("no response from API")
+		return nil, fmt.Errorf("no response from API")
+	}
+
+	return &chatResponse, nil
+}
+
+// newAPIError разбирает ответ об ошибке
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	var errResp errorResponse
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+		apiErr.Message = errResp.Error.Message
+		apiErr.Code = errResp.Error.Code
+	}
+	return apiErr
+}
+
+// backoff — экспоненциальная задержка с полным джиттером
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.baseDelay << attempt
+	if delay <= 0 || delay > c.maxDelay {
+		delay = c.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay))) + 1
+}
+
+func retryable(ctx context.Context, err error) bool {
+	// Вызывающий сам отменил запрос или исчерпал общий дедлайн
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
 	}
 
-	return chatResponse.Choices[0].Message.Content, nil
+	// Сетевые ошибки и таймаут отдельной попытки
+	return true
 }
@@ -0,0 +1,160 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const okResponse = `{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`
+
+// reply — ответ тестового сервера на одну попытку
+type reply struct {
+	status     int
+	retryAfter string
+	body       string
+}
+
+// newTestServer отвечает по replies, повторяя последний ответ, и считает попытки
+func newTestServer(t *testing.T, replies ...reply) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(replies) {
+			n = len(replies) - 1
+		}
+		if replies[n].retryAfter != "" {
+			w.Header().Set("Retry-After", replies[n].retryAfter)
+		}
+		w.WriteHeader(replies[n].status)
+		w.Write([]byte(replies[n].body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestClient(url string, maxRetries int, baseDelay time.Duration) *Client {
+	client := NewOpenAIClient("key", url, "test-model")
+	client.SetRetries(maxRetries, baseDelay, baseDelay)
+	return client
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		replies    []reply
+		maxRetries int
+		wantErr    error
+		wantCalls  int32
+		minElapsed time.Duration
+	}{
+		{
+			name:       "429 waits for Retry-After",
+			replies:    []reply{{status: 429, retryAfter: "1"}, {status: 200, body: okResponse}},
+			maxRetries: 3,
+			wantCalls:  2,
+			minElapsed: time.Second,
+		},
+		{
+			name:       "5xx gives up after retries",
+			replies:    []reply{{status: 503, body: `{"error": {"message": "overloaded"}}`}},
+			maxRetries: 2,
+			wantErr:    ErrUnavailable,
+			wantCalls:  3,
+		},
+		{
+			name:       "5xx recovers",
+			replies:    []reply{{status: 502}, {status: 500}, {status: 200, body: okResponse}},
+			maxRetries: 3,
+			wantCalls:  3,
+		},
+		{
+			name:       "400 is not retried",
+			replies:    []reply{{status: 400, body: `{"error": {"message": "bad prompt"}}`}},
+			maxRetries: 3,
+			wantErr:    ErrBadRequest,
+			wantCalls:  1,
+		},
+		{
+			name:       "401 is not retried",
+			replies:    []reply{{status: 401}},
+			maxRetries: 3,
+			wantErr:    ErrAuth,
+			wantCalls:  1,
+		},
+		{
+			name:       "exhausted quota is not retried",
+			replies:    []reply{{status: 429, body: `{"error": {"message": "no money", "code": "insufficient_quota"}}`}},
+			maxRetries: 3,
+			wantErr:    ErrQuota,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newTestServer(t, tt.replies...)
+			client := newTestClient(server.URL, tt.maxRetries, time.Millisecond)
+
+			start := time.Now()
+			got, err := client.SendRequestContext(context.Background(), "prompt")
+			elapsed := time.Since(start)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || got != "ok" {
+				t.Errorf("SendRequestContext = %q, %v; want ok", got, err)
+			}
+			if n := calls.Load(); n != tt.wantCalls {
+				t.Errorf("attempts = %d, want %d", n, tt.wantCalls)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("elapsed %v, want at least %v", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestClientStopsRetryingWhenCanceled(t *testing.T) {
+	server, calls := newTestServer(t, reply{status: 503})
+	client := newTestClient(server.URL, 5, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.SendRequestContext(ctx, "prompt")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("canceled request returned after %v", elapsed)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("attempts = %d, want 1 before the cancellation", n)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"5":                             5 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Fri, 01 Aug 2025 12:00:30 GMT": 30 * time.Second,
+		"Fri, 01 Aug 2025 11:59:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
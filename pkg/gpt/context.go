@@ -0,0 +1,44 @@
+package gpt
+
+import "context"
+
+// Контекст запроса несет сведения о вызывающем: для учета расхода
+// (WithUser, WithFeature) и кэша ответов (WithPromptVersion)
+
+type userKey struct{}
+type featureKey struct{}
+type promptVersionKey struct{}
+
+// WithUser отмечает, что запросы в ctx делаются для пользователя userID
+func WithUser(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// WithFeature отмечает, какая функция бота делает запросы в ctx
+func WithFeature(ctx context.Context, feature string) context.Context {
+	return context.WithValue(ctx, featureKey{}, feature)
+}
+
+// UserFrom возвращает пользователя из WithUser (0 — не указан)
+func UserFrom(ctx context.Context) int64 {
+	userID, _ := ctx.Value(userKey{}).(int64)
+	return userID
+}
+
+// FeatureFrom возвращает функцию из WithFeature ("" — не указана)
+func FeatureFrom(ctx context.Context) string {
+	feature, _ := ctx.Value(featureKey{}).(string)
+	return feature
+}
+
+// WithPromptVersion отмечает, что запросы в ctx детерминированы: промпт
+// version ("profile/v2") с одинаковыми данными можно отвечать из кэша
+func WithPromptVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, promptVersionKey{}, version)
+}
+
+// PromptVersionFrom возвращает версию из WithPromptVersion ("" — не кэшировать)
+func PromptVersionFrom(ctx context.Context) string {
+	version, _ := ctx.Value(promptVersionKey{}).(string)
+	return version
+}
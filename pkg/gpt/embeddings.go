@@ -0,0 +1,124 @@
+package gpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultEmbeddingModel — модель векторных представлений по умолчанию
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+type EmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type EmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// SetEmbeddingModel задает модель для Embed (пустая — по умолчанию)
+func (c *Client) SetEmbeddingModel(model string) {
+	if model == "" {
+		model = DefaultEmbeddingModel
+	}
+	c.embeddingModel = model
+}
+
+// EmbeddingModel возвращает модель, которой Embed считает векторы
+func (c *Client) EmbeddingModel() string {
+	return c.embeddingModel
+}
+
+// Embed возвращает векторы текстов в том же порядке (endpoint /embeddings)
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(EmbeddingRequest{Model: c.embeddingModel, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+
+	var response *EmbeddingResponse
+	err = c.retry(ctx, func() (err error) {
+		response, err = c.sendEmbeddings(ctx, jsonData)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range response.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("no embedding for input %d", i)
+		}
+	}
+
+	usage := EstimateUsage(strings.Join(texts, "\n"), "")
+	if response.Usage != nil {
+		usage = *response.Usage
+	}
+	recordUsage(ctx, usage)
+	if c.usageHook != nil {
+		c.usageHook(ctx, c.embeddingModel, usage)
+	}
+	return vectors, nil
+}
+
+// sendEmbeddings выполняет одну попытку запроса векторов
+func (c *Client) sendEmbeddings(ctx context.Context, jsonData []byte) (*EmbeddingResponse, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/embeddings", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var response EmbeddingResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,80 @@
+package gpt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Категории ошибок API. Проверяются через errors.Is:
+//
+//	if errors.Is(err, gpt.ErrRateLimited) { ... }
+var (
+	ErrRateLimited = errors.New("gpt: rate limited")
+	ErrAuth        = errors.New("gpt: authentication failed")
+	ErrQuota       = errors.New("gpt: quota exceeded")
+	ErrBadRequest  = errors.New("gpt: bad request")
+	ErrUnavailable = errors.New("gpt: service unavailable")
+)
+
+// APIError — ответ API с кодом, отличным от 200
+type APIError struct {
+	StatusCode int
+	Code       string // error.code из ответа, например "insufficient_quota"
+	Message    string
+	// RetryAfter — сколько подождать перед повтором, если API его указал
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("API error: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.Code == "insufficient_quota":
+		return ErrQuota
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrAuth
+	case e.StatusCode >= 500 || e.StatusCode == http.StatusRequestTimeout:
+		return ErrUnavailable
+	case e.StatusCode >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// Retryable сообщает, имеет ли смысл повторить запрос
+func (e *APIError) Retryable() bool {
+	if e.Code == "insufficient_quota" {
+		return false
+	}
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter понимает оба формата заголовка: секунды и HTTP-дату
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
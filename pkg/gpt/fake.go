@@ -0,0 +1,124 @@
+package gpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FakeClient отвечает по сценарию, не обращаясь к сети: для запуска бота
+// офлайн и для тестов. Ответ выбирается по первому правилу, все подстроки
+// Contains которого есть в промпте; если ни одно не подошло — Default.
+type FakeClient struct {
+	mutex    sync.Mutex
+	rules    []FakeRule
+	fallback string
+	// prompts — последние fakePromptsLimit промптов, чтобы долгий офлайн
+	// запуск бота не накапливал их без ограничения
+	prompts []string
+
+	// usageHook получает оценку расхода токенов (EstimateUsage)
+	usageHook UsageHook
+}
+
+// fakePromptsLimit — сколько последних промптов хранит FakeClient
+const fakePromptsLimit = 100
+
+type FakeRule struct {
+	Contains []string `json:"contains"`
+	Response string   `json:"response"`
+}
+
+// fakeScript — формат файла сценария (LLM_FAKE_SCRIPT)
+type fakeScript struct {
+	Rules   []FakeRule `json:"rules"`
+	Default string     `json:"default"`
+}
+
+// NewFakeClient создает провайдер со сценарием rules и ответом по умолчанию fallback
+func NewFakeClient(fallback string, rules ...FakeRule) *FakeClient {
+	return &FakeClient{rules: rules, fallback: fallback}
+}
+
+// LoadFakeClient читает сценарий из JSON файла:
+//
+//	{"rules": [{"contains": ["создания профиля"], "response": "{...}"}], "default": "{}"}
+func LoadFakeClient(path string) (*FakeClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var script fakeScript
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&script); err != nil {
+		return nil, err
+	}
+	return NewFakeClient(script.Default, script.Rules...), nil
+}
+
+func (c *FakeClient) SendRequest(prompt string) (string, error) {
+	return c.SendRequestContext(context.Background(), prompt)
+}
+
+func (c *FakeClient) SendRequestContext(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	response, hook := c.respond(prompt)
+	if progress := ProgressFrom(ctx); progress != nil && response != "" {
+		if err := c.stream(ctx, response, progress); err != nil {
+			return "", err
+		}
+	}
+	usage := EstimateUsage(prompt, response)
+	recordUsage(ctx, usage)
+	if hook != nil {
+		hook(ctx, "fake", usage)
+	}
+	return response, nil
+}
+
+// SetUsageHook задает получателя расхода токенов (nil — не учитывать)
+func (c *FakeClient) SetUsageHook(hook UsageHook) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.usageHook = hook
+}
+
+func (c *FakeClient) respond(prompt string) (string, UsageHook) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.prompts) == fakePromptsLimit {
+		c.prompts = append(c.prompts[:0], c.prompts[1:]...)
+	}
+	c.prompts = append(c.prompts, prompt)
+	for _, rule := range c.rules {
+		if rule.matches(prompt) {
+			return rule.Response, c.usageHook
+		}
+	}
+	return c.fallback, c.usageHook
+}
+
+// Prompts возвращает последние полученные промпты по порядку
+func (c *FakeClient) Prompts() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string(nil), c.prompts...)
+}
+
+func (r FakeRule) matches(prompt string) bool {
+	for _, s := range r.Contains {
+		if !strings.Contains(prompt, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package gpt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFakeClientKeepsLastPrompts(t *testing.T) {
+	client := NewFakeClient("{}")
+	for n := 0; n < fakePromptsLimit+10; n++ {
+		if _, err := client.SendRequest(fmt.Sprintf("prompt %d", n)); err != nil {
+			t.Fatalf("SendRequest: %v", err)
+		}
+	}
+
+	prompts := client.Prompts()
+	if len(prompts) != fakePromptsLimit {
+		t.Fatalf("len(Prompts) = %d, want %d", len(prompts), fakePromptsLimit)
+	}
+	if first, last := prompts[0], prompts[len(prompts)-1]; first != "prompt 10" || last != fmt.Sprintf("prompt %d", fakePromptsLimit+9) {
+		t.Errorf("Prompts = [%q ... %q], want the most recent ones in order", first, last)
+	}
+}
@@ -0,0 +1,123 @@
+package gpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidJSON — модель так и не вернула JSON, подходящий под схему
+var ErrInvalidJSON = errors.New("gpt: invalid JSON response")
+
+// JSONRequester — провайдер, умеющий сам ограничить ответ JSON
+// (response_format в OpenAI-совместимом API)
+type JSONRequester interface {
+	SendJSONRequestContext(ctx context.Context, prompt string) (string, error)
+}
+
+// SchemaError — ответ модели не прошел проверку схемы
+type SchemaError struct {
+	Errors   []string
+	Response string
+}
+
+func (e *SchemaError) Error() string {
+	return "invalid JSON response: " + strings.Join(e.Errors, "; ")
+}
+
+func (e *SchemaError) Unwrap() error {
+	return ErrInvalidJSON
+}
+
+// DefaultRepairs — сколько раз переспросить модель, если ответ не подошел под схему
+const DefaultRepairs = 2
+
+// CompleteJSON запрашивает у модели JSON, снимает обертку (```json, пояснения),
+// проверяет результат по schema и при ошибках переспрашивает модель, показывая
+// ей найденные ошибки, не больше repairs раз.
+func CompleteJSON(ctx context.Context, llm LLM, prompt string, schema *Schema, repairs int) (map[string]interface{}, error) {
+	request := prompt
+	for attempt := 0; ; attempt++ {
+		response, err := sendJSON(ctx, llm, request)
+		if err != nil {
+			return nil, err
+		}
+
+		result, errs := decodeJSON(response, schema)
+		if len(errs) == 0 {
+			return result, nil
+		}
+		// Негодный ответ не должен вернуться из кэша при повторном извлечении
+		if invalidator, ok := llm.(Invalidator); ok {
+			invalidator.Invalidate(ctx, request)
+		}
+		if attempt >= repairs {
+			return nil, &SchemaError{Errors: errs, Response: response}
+		}
+
+		request = repairPrompt(prompt, response, errs)
+	}
+}
+
+func sendJSON(ctx context.Context, llm LLM, prompt string) (string, error) {
+	if requester, ok := llm.(JSONRequester); ok {
+		return requester.SendJSONRequestContext(ctx, prompt)
+	}
+	return llm.SendRequestContext(ctx, prompt)
+}
+
+func decodeJSON(response string, schema *Schema) (map[string]interface{}, []string) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(ExtractJSON(response)), &value); err != nil {
+		return nil, []string{"response is not valid JSON: " + err.Error()}
+	}
+
+	result, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, []string{"response must be a JSON object"}
+	}
+	if schema != nil {
+		if errs := schema.Validate(result); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+	return result, nil
+}
+
+func repairPrompt(prompt, response string, errs []string) string {
+	return fmt.Sprintf(`%s
+
+Твой предыдущий ответ не подошел:
+%s
+
+Ошибки:
+- %s
+
+Верни только исправленный JSON, без пояснений и без markdown.`, prompt, response, strings.Join(errs, "\n- "))
+}
+
+// ExtractJSON вырезает JSON объект из ответа модели: снимает ```json ограждение
+// и текст до первой { и после последней }
+func ExtractJSON(response string) string {
+	text := strings.TrimSpace(response)
+
+	if start := strings.Index(text, "```"); start >= 0 {
+		fenced := text[start+3:]
+		if newline := strings.IndexByte(fenced, '\n'); newline >= 0 {
+			fenced = fenced[newline+1:]
+		}
+		if end := strings.Index(fenced, "```"); end >= 0 {
+			fenced = fenced[:end]
+		}
+		text = strings.TrimSpace(fenced)
+	}
+
+	start := strings.IndexByte(text, '{')
+	end := strings.LastIndexByte(text, '}')
+	if start >= 0 && end > start {
+		return text[start : end+1]
+	}
+	return text
+}
@@ -0,0 +1,94 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// scriptedLLM отвечает по очереди ответами responses и запоминает промпты
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (s *scriptedLLM) SendRequest(prompt string) (string, error) {
+	return s.SendRequestContext(context.Background(), prompt)
+}
+
+func (s *scriptedLLM) SendRequestContext(_ context.Context, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	response := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+	return response, nil
+}
+
+func TestCompleteJSONRepairs(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{
+		`{"name": "Анна", "skills": {"Go": 9}}`,
+		"Исправил:\n```json\n{\"name\": \"Анна\", \"skills\": {\"Go\": 5}}\n```",
+	}}
+
+	result, err := CompleteJSON(context.Background(), llm, "Извлеки профиль", testSchema, DefaultRepairs)
+	if err != nil {
+		t.Fatalf("CompleteJSON: %v", err)
+	}
+	if skills := result["skills"].(map[string]interface{}); skills["Go"] != 5.0 {
+		t.Errorf("result = %v, want the repaired answer", result)
+	}
+
+	if len(llm.prompts) != 2 {
+		t.Fatalf("prompts = %d, want the original and one repair", len(llm.prompts))
+	}
+	repair := llm.prompts[1]
+	for _, want := range []string{"Извлеки профиль", `{"name": "Анна", "skills": {"Go": 9}}`, "$.skills.Go: must be <= 5"} {
+		if !strings.Contains(repair, want) {
+			t.Errorf("repair prompt does not contain %q:\n%s", want, repair)
+		}
+	}
+}
+
+func TestCompleteJSONGivesUp(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{"не знаю"}}
+
+	_, err := CompleteJSON(context.Background(), llm, "Извлеки профиль", testSchema, 2)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || !errors.Is(err, ErrInvalidJSON) {
+		t.Fatalf("err = %v, want SchemaError wrapping ErrInvalidJSON", err)
+	}
+	if schemaErr.Response != "не знаю" {
+		t.Errorf("SchemaError.Response = %q, want the last response", schemaErr.Response)
+	}
+	if len(llm.prompts) != 3 {
+		t.Errorf("prompts = %d, want the original and 2 repairs", len(llm.prompts))
+	}
+}
+
+func TestCompleteJSONWithoutRepairs(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{`["not", "an", "object"]`}}
+
+	if _, err := CompleteJSON(context.Background(), llm, "prompt", nil, 0); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("err = %v, want ErrInvalidJSON", err)
+	}
+	if len(llm.prompts) != 1 {
+		t.Errorf("prompts = %d, want no repairs", len(llm.prompts))
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := map[string]string{
+		`{"a": 1}`:                 `{"a": 1}`,
+		"```json\n{\"a\": 1}\n```": `{"a": 1}`,
+		"```\n{\"a\": 1}\n```":     `{"a": 1}`,
+		"Вот ответ: {\"a\": {\"b\": 2}} Надеюсь, так": `{"a": {"b": 2}}`,
+		"нет JSON": "нет JSON",
+	}
+	for response, want := range tests {
+		if got := ExtractJSON(response); got != want {
+			t.Errorf("ExtractJSON(%q) = %q, want %q", response, got, want)
+		}
+	}
+}
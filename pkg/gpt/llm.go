@@ -0,0 +1,15 @@
+package gpt
+
+import "context"
+
+// LLM — языковая модель, которой бот отправляет промпты.
+// Реализации: Client (OpenAI-совместимый API) и FakeClient (сценарий без сети).
+type LLM interface {
+	SendRequestContext(ctx context.Context, prompt string) (string, error)
+}
+
+var (
+	_ LLM           = (*Client)(nil)
+	_ LLM           = (*FakeClient)(nil)
+	_ JSONRequester = (*Client)(nil)
+)
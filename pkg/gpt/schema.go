@@ -0,0 +1,121 @@
+package gpt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema — подмножество JSON Schema, достаточное для проверки ответов модели
+type Schema struct {
+	Type       string // object, array, string, number, integer, boolean
+	Properties map[string]*Schema
+	Required   []string
+	// AdditionalProperties — схема значений для объектов-словарей
+	// (например, навык -> уровень); nil — любые дополнительные поля
+	AdditionalProperties *Schema
+	Items                *Schema
+	Enum                 []string
+	Minimum              *float64
+	Maximum              *float64
+}
+
+// Bound возвращает указатель для Minimum и Maximum
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// Validate возвращает список ошибок с путями до полей; пустой — значение подходит
+func (s *Schema) Validate(value interface{}) []string {
+	var errs []string
+	s.validate("$", value, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, value interface{}, errs *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object")
+			return
+		}
+		for _, key := range s.Required {
+			if v, ok := obj[key]; !ok || v == nil {
+				fail("missing required field %q", key)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if obj[key] == nil {
+				continue
+			}
+			if prop, ok := s.Properties[key]; ok {
+				prop.validate(path+"."+key, obj[key], errs)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(path+"."+key, obj[key], errs)
+			}
+		}
+
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			fail("expected array")
+			return
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("expected string")
+			return
+		}
+		if len(s.Enum) > 0 && !containsFold(s.Enum, str) {
+			fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			fail("expected %s", s.Type)
+			return
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			fail("expected integer")
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			fail("must be >= %g", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("must be <= %g", *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean")
+		}
+	}
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
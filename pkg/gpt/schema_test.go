@@ -0,0 +1,60 @@
+package gpt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testSchema = &Schema{
+	Type:     "object",
+	Required: []string{"name", "skills"},
+	Properties: map[string]*Schema{
+		"name":  {Type: "string"},
+		"level": {Type: "string", Enum: []string{"junior", "middle", "senior"}},
+		"hours": {Type: "integer", Minimum: Bound(0), Maximum: Bound(168)},
+		"tags":  {Type: "array", Items: &Schema{Type: "string"}},
+		"skills": {
+			Type:                 "object",
+			AdditionalProperties: &Schema{Type: "number", Minimum: Bound(1), Maximum: Bound(5)},
+		},
+	},
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string // подстроки ошибок; пусто — значение подходит
+	}{
+		{"valid", `{"name": "Анна", "level": "Senior", "hours": 20, "tags": ["go"], "skills": {"Go": 4}}`, nil},
+		{"null optional field", `{"name": "Анна", "level": null, "skills": {}}`, nil},
+		{"missing required", `{"skills": {}}`, []string{`$: missing required field "name"`}},
+		{"null required", `{"name": null, "skills": {}}`, []string{`missing required field "name"`}},
+		{"enum", `{"name": "Анна", "level": "lead", "skills": {}}`, []string{"$.level: must be one of junior, middle, senior"}},
+		{"minimum", `{"name": "Анна", "hours": -1, "skills": {}}`, []string{"$.hours: must be >= 0"}},
+		{"maximum", `{"name": "Анна", "hours": 200, "skills": {}}`, []string{"$.hours: must be <= 168"}},
+		{"integer", `{"name": "Анна", "hours": 1.5, "skills": {}}`, []string{"$.hours: expected integer"}},
+		{"additional properties", `{"name": "Анна", "skills": {"Go": 7, "SQL": "много"}}`, []string{"$.skills.Go: must be <= 5", "$.skills.SQL: expected number"}},
+		{"array items", `{"name": "Анна", "tags": ["go", 1], "skills": {}}`, []string{"$.tags[1]: expected string"}},
+		{"wrong type", `{"name": 5, "skills": []}`, []string{"$.name: expected string", "$.skills: expected object"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			errs := testSchema.Validate(value)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Validate = %q, want %d errors %q", errs, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("error %d = %q, want %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
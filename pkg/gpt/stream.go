@@ -0,0 +1,147 @@
+package gpt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ProgressFunc получает текст ответа, полученный к этому моменту. При повторе
+// запроса текст начинается заново.
+type ProgressFunc func(text string)
+
+type progressKey struct{}
+
+// WithProgress просит передавать ответ по мере генерации: Client включает
+// потоковую передачу (SSE), FakeClient выдает ответ по частям
+func WithProgress(ctx context.Context, progress ProgressFunc) context.Context {
+	if progress == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// ProgressFrom возвращает функцию из WithProgress (nil — ответ нужен целиком)
+func ProgressFrom(ctx context.Context) ProgressFunc {
+	progress, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return progress
+}
+
+// StreamOptions{IncludeUsage: true} просит прислать расход токенов последним событием
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// streamChunk — событие потоковой передачи chat/completions
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// sendStream выполняет одну попытку потокового запроса, передавая текст в progress
+func (c *Client) sendStream(ctx context.Context, jsonData []byte, progress ProgressFunc) (*ChatResponse, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, newAPIError(resp, body)
+	}
+
+	var content strings.Builder
+	var usage *Usage
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("stream: %w", err)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+			progress(content.String())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("no response from API")
+	}
+	return &ChatResponse{
+		Choices: []Choice{{Message: Message{Role: RoleAssistant, Content: content.String()}}},
+		Usage:   usage,
+	}, nil
+}
+
+// fakeChunk — размер части ответа FakeClient в символах и пауза между частями
+const (
+	fakeChunk      = 24
+	fakeChunkDelay = 20 * time.Millisecond
+)
+
+// stream выдает ответ по частям, как это делает потоковая передача
+func (c *FakeClient) stream(ctx context.Context, response string, progress ProgressFunc) error {
+	runes := []rune(response)
+	for end := fakeChunk; ; end += fakeChunk {
+		if end > len(runes) {
+			end = len(runes)
+		}
+		progress(string(runes[:end]))
+		if end == len(runes) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fakeChunkDelay):
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package gpt
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// Usage — расход токенов одного запроса (поле usage ответа OpenAI)
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// UsageHook получает расход каждого успешного запроса. ctx — контекст запроса:
+// из него UserFrom и FeatureFrom достают, кому и зачем отнести расход.
+type UsageHook func(ctx context.Context, model string, usage Usage)
+
+type usageKey struct{}
+
+// WithUsage просит добавлять в *usage расход запросов, сделанных с ctx:
+// фактический, если провайдер его сообщил, иначе оценку. Так вызывающий
+// узнает расход, не зная, какая модель стоит за LLM. Запросы с ctx должны
+// идти последовательно.
+func WithUsage(ctx context.Context, usage *Usage) context.Context {
+	return context.WithValue(ctx, usageKey{}, usage)
+}
+
+// recordUsage добавляет расход запроса в счетчик из WithUsage
+func recordUsage(ctx context.Context, usage Usage) {
+	if total, ok := ctx.Value(usageKey{}).(*Usage); ok && total != nil {
+		total.PromptTokens += usage.PromptTokens
+		total.CompletionTokens += usage.CompletionTokens
+		total.TotalTokens += usage.TotalTokens
+	}
+}
+
+// EstimateUsage грубо оценивает расход, когда провайдер его не сообщает:
+// около четырех символов на токен
+func EstimateUsage(prompt, response string) Usage {
+	usage := Usage{
+		PromptTokens:     (utf8.RuneCountInString(prompt) + 3) / 4,
+		CompletionTokens: (utf8.RuneCountInString(response) + 3) / 4,
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}